@@ -0,0 +1,51 @@
+// /proyecto1/ambiguity.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"proyecto1/ambiguity"
+	"proyecto1/config"
+)
+
+// cmdAmbiguity decide si la regex es ambigua (alguna cadena con dos árboles de derivación) y con qué
+// grado. Muestra la cadena testigo más corta, dos árboles distintos para ella y, si la cantidad de árboles
+// crece sin cota, la cadena que al repetirse la hace crecer. Sale con exitFalse si la regex es ambigua.
+func cmdAmbiguity(fs *flag.FlagSet) cliRun {
+	return func(cf *cliFlags, args []string) (int, error) {
+		if cf.load != "" {
+			return exitUsage, errUsage("ambiguity necesita una regex (no admite -load): el análisis usa su AST")
+		}
+		c, rest, err := cf.inputNFA(args)
+		if err != nil {
+			return exitError, err
+		}
+		if err := noMoreArgs(rest); err != nil {
+			return exitUsage, err
+		}
+		res, err := ambiguity.Analyze(c.AST)
+		if err != nil {
+			return exitError, err
+		}
+		cf.emit(res, func(w io.Writer) {
+			fmt.Fprintf(w, "Regex: %s\n", c.AST)
+			fmt.Fprintf(w, "Ambigua: %s\n", yesNo(res.Ambiguous))
+			if !res.Ambiguous {
+				return
+			}
+			fmt.Fprintf(w, "Grado: %s\n", res.Degree)
+			fmt.Fprintf(w, "Testigo: %s\n", config.FormatWord(res.Witness))
+			fmt.Fprintf(w, "Árbol 1: %s\n", res.Trees[0])
+			fmt.Fprintf(w, "Árbol 2: %s\n", res.Trees[1])
+			if res.Loop != "" {
+				fmt.Fprintf(w, "Bucle: %s (cada repetición multiplica los árboles)\n", config.FormatWord(res.Loop))
+			}
+		})
+		if res.Ambiguous {
+			return exitFalse, nil
+		}
+		return exitOK, nil
+	}
+}
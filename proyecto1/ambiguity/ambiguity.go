@@ -0,0 +1,445 @@
+// Package ambiguity decide si una expresión regular es ambigua, es decir, si alguna cadena tiene más de
+// un árbol de derivación, y clasifica el grado de la ambigüedad (finito, polinomial o exponencial).
+//
+// El análisis usa una construcción de Thompson en la que cada transición lleva el fragmento del árbol
+// que produce, de modo que cada camino de aceptación se lee como un árbol de derivación. Se consideran
+// los árboles sin iteraciones vacías de la estrella (en (a*)* la cadena "a" tiene un solo árbol, no uno
+// por cada iteración vacía): son los caminos en los que ninguna iteración de una estrella empieza y
+// termina en un mismo tramo de transiciones ε.
+// Al eliminar las transiciones ε se obtiene un autómata de posiciones (como el de Glushkov) con
+// transiciones múltiples, sobre el que se aplican los criterios de Weber y Seidl.
+package ambiguity
+
+import (
+	"fmt"
+	"strings"
+
+	"proyecto1/regex"
+)
+
+// Degree es el grado de ambigüedad: cómo crece, con la longitud de la cadena, la cantidad máxima de
+// árboles de derivación.
+type Degree int
+
+const (
+	Unambiguous Degree = iota // Cada cadena tiene a lo sumo un árbol
+	Finite                    // Acotada por una constante, como (a|a)
+	Polynomial                // Crece polinomialmente, como a*a* (n+1 árboles para aⁿ)
+	Exponential               // Crece exponencialmente, como (a|a)* (2ⁿ árboles para aⁿ)
+)
+
+// String retorna el nombre del grado.
+func (d Degree) String() string {
+	switch d {
+	case Finite:
+		return "finita"
+	case Polynomial:
+		return "polinomial"
+	case Exponential:
+		return "exponencial"
+	}
+	return "no ambigua"
+}
+
+// MarshalText permite que el grado aparezca por nombre en JSON.
+func (d Degree) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// Result es el resultado del análisis de ambigüedad.
+type Result struct {
+	Ambiguous bool     `json:"ambiguous"`
+	Degree    Degree   `json:"degree"`
+	Witness   string   `json:"witness"`         // Cadena más corta con dos árboles (si es ambigua)
+	Trees     []string `json:"trees,omitempty"` // Dos árboles distintos para el testigo
+	Loop      string   `json:"loop,omitempty"`  // Con grado polinomial o exponencial, una cadena v que al repetirse multiplica los árboles
+}
+
+// maxSteps limita la enumeración de caminos ε simples, que puede ser exponencial en regex grandes.
+const maxSteps = 1_000_000
+
+// Analyze decide si la regex es ambigua y clasifica su grado.
+// Los árboles se escriben como valores: a (literal), ε, (v1, v2) (concatenación), izq(v) y der(v)
+// (unión) y [v1, v2, ...] (estrella).
+func Analyze(ast *regex.Node) (Result, error) {
+	if ast == nil {
+		return Result{}, fmt.Errorf("AST vacío")
+	}
+	a, err := positionAutomaton(ast)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{}
+	start := pair{a.start, a.start, false}
+	path, end, ok := pairPath(nil), start, a.isAmbiguousEnd(start)
+	if !ok {
+		path, end, ok = a.search(start, a.isAmbiguousEnd)
+	}
+	if !ok {
+		return res, nil
+	}
+	res.Ambiguous, res.Degree = true, Finite
+	res.Witness = path.word()
+	t1, t2 := path.trees()
+	if end.div {
+		res.Trees = []string{t1 + a.final[end.p][0], t2 + a.final[end.q][0]}
+	} else {
+		res.Trees = []string{t1 + a.final[end.p][0], t2 + a.final[end.p][1]}
+	}
+
+	useful := a.useful()
+	// Exponencial: dos ciclos distintos p → p con la misma cadena
+	for p := range a.edges {
+		if !useful[p] {
+			continue
+		}
+		if loop, _, ok := a.search(pair{p, p, false}, func(s pair) bool { return s == pair{p, p, true} }); ok {
+			res.Degree, res.Loop = Exponential, loop.word()
+			return res, nil
+		}
+	}
+	// Polinomial: p ≠ q y una cadena v con caminos p → p, p → q y q → q
+	for p := range a.edges {
+		for q := range a.edges {
+			if p == q || !useful[p] || !useful[q] {
+				continue
+			}
+			if v, ok := a.searchTriple(p, q); ok {
+				res.Degree, res.Loop = Polynomial, v
+				return res, nil
+			}
+		}
+	}
+	return res, nil
+}
+
+// -------------------------- Construcción anotada --------------------------
+
+// epsEdge es una transición ε que escribe text en el árbol. enter y leave identifican la estrella
+// (desde 1) cuya iteración empieza o termina en la transición; 0 si no es el caso.
+type epsEdge struct {
+	to           int
+	text         string
+	enter, leave int
+}
+
+// symEdge es una transición con un símbolo (el de un literal de la regex).
+type symEdge struct {
+	sym rune
+	to  int
+}
+
+// thompson es el autómata de Thompson anotado: cada nodo del AST tiene sus propios estados inicial y
+// final, unidos a los de sus hijos por transiciones ε que escriben los delimitadores del árbol.
+type thompson struct {
+	eps   [][]epsEdge
+	syms  [][]symEdge
+	stars int
+}
+
+func (t *thompson) newState() int {
+	t.eps = append(t.eps, nil)
+	t.syms = append(t.syms, nil)
+	return len(t.eps) - 1
+}
+
+func (t *thompson) epsilon(from, to int, text string) {
+	t.eps[from] = append(t.eps[from], epsEdge{to: to, text: text})
+}
+
+// build construye el fragmento del nodo y retorna sus estados inicial y final.
+func (t *thompson) build(n *regex.Node) (int, int) {
+	s, f := t.newState(), t.newState()
+	switch n.Kind {
+	case regex.Literal:
+		if n.Val == 'ε' {
+			t.epsilon(s, f, "ε")
+		} else {
+			t.syms[s] = append(t.syms[s], symEdge{n.Val, f})
+		}
+	case regex.Concat:
+		ls, lf := t.build(n.Left)
+		rs, rf := t.build(n.Right)
+		t.epsilon(s, ls, "(")
+		t.epsilon(lf, rs, ", ")
+		t.epsilon(rf, f, ")")
+	case regex.Union:
+		ls, lf := t.build(n.Left)
+		rs, rf := t.build(n.Right)
+		t.epsilon(s, ls, "izq(")
+		t.epsilon(s, rs, "der(")
+		t.epsilon(lf, f, ")")
+		t.epsilon(rf, f, ")")
+	case regex.Star:
+		t.stars++
+		star := t.stars
+		xs, xf := t.build(n.Left)
+		t.eps[s] = append(t.eps[s], epsEdge{to: xs, text: "[", enter: star})
+		t.eps[xf] = append(t.eps[xf], epsEdge{to: xs, text: ", ", enter: star, leave: star})
+		t.eps[xf] = append(t.eps[xf], epsEdge{to: f, text: "]", leave: star})
+		t.epsilon(s, f, "[]")
+	}
+	return s, f
+}
+
+// -------------------------- Autómata de posiciones --------------------------
+
+// edge es una transición del autómata sin ε. Cada texto es una copia distinta de la transición (un
+// camino distinto en el autómata de Thompson) con el fragmento de árbol que escribe; se guardan hasta
+// dos, que bastan para distinguir caminos.
+type edge struct {
+	sym   rune
+	to    int
+	texts []string
+}
+
+// automaton es el autómata sin ε: sus estados son el inicial y los destinos de las transiciones con
+// símbolo (las posiciones de la regex). final[p] son los fragmentos de los caminos ε de p a la aceptación.
+type automaton struct {
+	start int
+	edges [][]edge
+	final [][]string
+}
+
+// addText agrega un texto a la lista, hasta dos.
+func addText(texts []string, text string) []string {
+	if len(texts) < 2 {
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+// positionAutomaton elimina las transiciones ε del autómata de Thompson anotado, recorriendo desde cada
+// posición los caminos ε sin iteraciones vacías: en un mismo tramo no se termina una iteración que empezó
+// en él. Estos caminos no repiten estados, porque volver a un estado requiere pasar por una iteración vacía.
+func positionAutomaton(ast *regex.Node) (*automaton, error) {
+	t := &thompson{}
+	start, accept := t.build(ast)
+
+	index := map[int]int{start: 0}
+	order := []int{start}
+	a := &automaton{}
+	steps := 0
+	for i := 0; i < len(order); i++ {
+		p := order[i]
+		var edges []edge
+		var final []string
+		entered := make([]bool, t.stars+1) // entered[k]: la iteración actual de la estrella k empezó en este tramo
+		var text strings.Builder
+		var dfs func(q int) error
+		dfs = func(q int) error {
+			if steps++; steps > maxSteps {
+				return fmt.Errorf("la regex es demasiado grande para el análisis de ambigüedad")
+			}
+			if q == accept {
+				final = addText(final, text.String())
+			}
+			for _, se := range t.syms[q] {
+				if _, ok := index[se.to]; !ok {
+					index[se.to] = len(order)
+					order = append(order, se.to)
+				}
+				to, found := index[se.to], false
+				for k := range edges {
+					if edges[k].sym == se.sym && edges[k].to == to {
+						edges[k].texts = addText(edges[k].texts, text.String()+string(se.sym))
+						found = true
+					}
+				}
+				if !found {
+					edges = append(edges, edge{sym: se.sym, to: to, texts: []string{text.String() + string(se.sym)}})
+				}
+			}
+			for _, ee := range t.eps[q] {
+				if ee.leave > 0 && entered[ee.leave] {
+					continue // Iteración vacía de una estrella
+				}
+				saved := text.Len()
+				text.WriteString(ee.text)
+				entered[ee.enter] = ee.enter > 0
+				err := dfs(ee.to)
+				entered[ee.enter] = false
+				if err != nil {
+					return err
+				}
+				rest := text.String()[:saved]
+				text.Reset()
+				text.WriteString(rest)
+			}
+			return nil
+		}
+		if err := dfs(p); err != nil {
+			return nil, err
+		}
+		a.edges = append(a.edges, edges)
+		a.final = append(a.final, final)
+	}
+	return a, nil
+}
+
+// useful retorna los estados alcanzables desde el inicial desde los que se alcanza uno final.
+func (a *automaton) useful() []bool {
+	reach := make([]bool, len(a.edges))
+	reach[a.start] = true
+	stack := []int{a.start}
+	pred := make([][]int, len(a.edges))
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range a.edges[p] {
+			pred[e.to] = append(pred[e.to], p)
+			if !reach[e.to] {
+				reach[e.to] = true
+				stack = append(stack, e.to)
+			}
+		}
+	}
+	co := make([]bool, len(a.edges))
+	for p, f := range a.final {
+		if reach[p] && len(f) > 0 {
+			co[p] = true
+			stack = append(stack, p)
+		}
+	}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, q := range pred[p] {
+			if !co[q] {
+				co[q] = true
+				stack = append(stack, q)
+			}
+		}
+	}
+	for p := range co {
+		co[p] = co[p] && reach[p]
+	}
+	return co
+}
+
+// -------------------------- Producto de caminos --------------------------
+
+// pair es un estado del producto del autómata consigo mismo: dos caminos que leyeron la misma cadena,
+// en los estados p y q; div indica si los caminos ya se separaron (tomaron transiciones distintas).
+type pair struct {
+	p, q int
+	div  bool
+}
+
+// pairStep es el paso de la búsqueda que llegó a un estado del producto.
+type pairStep struct {
+	prev         pair
+	sym          rune
+	text1, text2 string
+}
+
+// pairPath es un camino en el producto: los pasos, en orden.
+type pairPath []pairStep
+
+// word retorna la cadena leída por el camino.
+func (pp pairPath) word() string {
+	var b strings.Builder
+	for _, st := range pp {
+		b.WriteRune(st.sym)
+	}
+	return b.String()
+}
+
+// trees retorna los fragmentos de árbol de cada uno de los dos caminos.
+func (pp pairPath) trees() (string, string) {
+	var t1, t2 strings.Builder
+	for _, st := range pp {
+		t1.WriteString(st.text1)
+		t2.WriteString(st.text2)
+	}
+	return t1.String(), t2.String()
+}
+
+// isAmbiguousEnd indica si dos caminos que llegan a s forman dos caminos de aceptación distintos.
+func (a *automaton) isAmbiguousEnd(s pair) bool {
+	if s.div {
+		return len(a.final[s.p]) > 0 && len(a.final[s.q]) > 0
+	}
+	return len(a.final[s.p]) > 1
+}
+
+// search hace una búsqueda en anchura en el producto desde start hasta un estado distinto de start que
+// cumpla goal, y retorna el camino más corto y el estado alcanzado.
+func (a *automaton) search(start pair, goal func(pair) bool) (pairPath, pair, bool) {
+	parent := map[pair]pairStep{}
+	seen := map[pair]bool{start: true}
+	queue := []pair{start}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for k1, e1 := range a.edges[s.p] {
+			for k2, e2 := range a.edges[s.q] {
+				if e1.sym != e2.sym {
+					continue
+				}
+				for i, t1 := range e1.texts {
+					for j, t2 := range e2.texts {
+						next := pair{e1.to, e2.to, s.div || k1 != k2 || i != j}
+						if seen[next] {
+							continue
+						}
+						seen[next] = true
+						parent[next] = pairStep{prev: s, sym: e1.sym, text1: t1, text2: t2}
+						if goal(next) {
+							var path pairPath
+							for c := next; c != start; c = parent[c].prev {
+								path = append(pairPath{parent[c]}, path...)
+							}
+							return path, next, true
+						}
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+	return nil, pair{}, false
+}
+
+// searchTriple busca una cadena v no vacía con caminos p → p, p → q y q → q, recorriendo el producto
+// triple desde (p, p, q) hasta (p, q, q).
+func (a *automaton) searchTriple(p, q int) (string, bool) {
+	type triple struct{ x, y, z int }
+	type tripleStep struct {
+		prev triple
+		sym  rune
+	}
+	start, goal := triple{p, p, q}, triple{p, q, q}
+	parent := map[triple]tripleStep{}
+	queue := []triple{start}
+	seen := map[triple]bool{start: true}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, ex := range a.edges[s.x] {
+			for _, ey := range a.edges[s.y] {
+				if ey.sym != ex.sym {
+					continue
+				}
+				for _, ez := range a.edges[s.z] {
+					if ez.sym != ex.sym {
+						continue
+					}
+					next := triple{ex.to, ey.to, ez.to}
+					if next == goal {
+						word := []rune{ex.sym}
+						for c := s; c != start; c = parent[c].prev {
+							word = append([]rune{parent[c].sym}, word...)
+						}
+						return string(word), true
+					}
+					if !seen[next] {
+						seen[next] = true
+						parent[next] = tripleStep{s, ex.sym}
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+	return "", false
+}
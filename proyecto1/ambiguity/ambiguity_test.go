@@ -0,0 +1,76 @@
+package ambiguity
+
+import (
+	"strings"
+	"testing"
+
+	"proyecto1/config"
+	"proyecto1/regex"
+)
+
+// parse construye el AST de la regex igual que el pipeline.
+func parse(t *testing.T, r string) *regex.Node {
+	t.Helper()
+	ast, err := regex.BuildAST(config.InfixToPostfix(config.FormatRegex(config.ExpandRegexExtensions(r))))
+	if err != nil {
+		t.Fatalf("%s: %v", r, err)
+	}
+	return ast
+}
+
+func TestAnalyzeDegree(t *testing.T) {
+	tests := []struct {
+		regex   string
+		degree  Degree
+		witness string
+	}{
+		{"(a|a)*", Exponential, "a"},
+		{"(a*)*a", Exponential, "aaa"}, // Sin iteraciones vacías: una composición de aⁿ⁻¹ por árbol
+		{"a*a*", Polynomial, "a"},
+		{"a|a", Finite, "a"},
+		{"(a|ab)(c|bc)", Finite, "abc"},
+		{"ab*", Unambiguous, ""},
+		{"(a|ε)*", Unambiguous, ""},
+		{"(a|b)*abb", Unambiguous, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			res, err := Analyze(parse(t, tt.regex))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Degree != tt.degree || res.Ambiguous != (tt.degree != Unambiguous) {
+				t.Errorf("grado %s (ambigua: %v), se esperaba %s", res.Degree, res.Ambiguous, tt.degree)
+			}
+			if res.Witness != tt.witness {
+				t.Errorf("testigo %q, se esperaba %q", res.Witness, tt.witness)
+			}
+			if !res.Ambiguous {
+				return
+			}
+			if len(res.Trees) != 2 || res.Trees[0] == res.Trees[1] {
+				t.Errorf("se esperaban dos árboles distintos, se obtuvo %q", res.Trees)
+			}
+			if (res.Loop != "") != (tt.degree >= Polynomial) {
+				t.Errorf("ciclo %q con grado %s", res.Loop, res.Degree)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTrees(t *testing.T) {
+	res, err := Analyze(parse(t, "a|a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(res.Trees, " ")
+	if got != "izq(a) der(a)" && got != "der(a) izq(a)" {
+		t.Errorf("árboles %q, se esperaban izq(a) y der(a)", res.Trees)
+	}
+}
+
+func TestAnalyzeNil(t *testing.T) {
+	if _, err := Analyze(nil); err == nil {
+		t.Errorf("Analyze(nil) no retornó un error")
+	}
+}
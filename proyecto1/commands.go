@@ -0,0 +1,489 @@
+// /proyecto1/commands.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"proyecto1/config"
+	"proyecto1/graphviz"
+	"proyecto1/nfa"
+	"proyecto1/regex"
+)
+
+// Códigos de salida de los subcomandos.
+const (
+	exitOK    = 0 // Éxito (match: todas las cadenas aceptadas; equiv: lenguajes equivalentes)
+	exitFalse = 1 // Veredicto negativo: alguna cadena rechazada, lenguajes distintos o lenguaje vacío
+	exitUsage = 2 // Flags o argumentos inválidos
+	exitError = 3 // Regex o autómata inválido, o error de lectura o escritura
+)
+
+// subcommand es un subcomando de la línea de comandos: proyecto1 <name> [flags] args...
+type subcommand struct {
+	name, usage, help string
+	run               func(args []string) int // Retorna el código de salida
+}
+
+// subcommands lista los subcomandos en el orden en que los muestra proyecto1 help.
+var subcommands []subcommand
+
+func init() {
+	subcommands = []subcommand{
+		{"build", "build [flags] (regex | -load archivo)", "muestra las etapas de la regex y los estados de cada autómata", cliCommand("build", cmdBuild)},
+		{"match", "match [flags] (regex | -load archivo) w...", "evalúa las cadenas (ε es la vacía); sale con 1 si alguna es rechazada", cliCommand("match", cmdMatch)},
+		{"minimize", "minimize [flags] [-o archivo] (regex | -load archivo)", "minimiza el DFA con el algoritmo de -min", cliCommand("minimize", cmdMinimize)},
+		{"equiv", "equiv [flags] (regex1 | -load archivo) regex2", "compara dos lenguajes; sale con 1 y una cadena testigo si difieren", cliCommand("equiv", cmdEquiv)},
+		{"render", "render [flags] [-stage min] [-o archivo] (regex | -load archivo)", "dibuja una etapa (.svg, .png, .dot, .mmd, .tex o .md; SVG a stdout sin -o)", cliCommand("render", cmdRender)},
+		{"enumerate", "enumerate [flags] [-n 20] [-maxlen k] (regex | -load archivo)", "lista las cadenas del lenguaje en orden canónico; sale con 1 si es vacío", cliCommand("enumerate", cmdEnumerate)},
+		{"convert", "convert [flags] [-stage input] (regex | -load archivo) salida", "guarda una etapa en JSON (.json, - para stdout) o JFLAP (.jff)", cliCommand("convert", cmdConvert)},
+		{"verify", "verify [flags] [-k 6] (regex | -load archivo) [w...]", "compara NFA, DFA y DFA minimizado en las cadenas y en todas las de longitud ≤ k", cliCommand("verify", cmdVerify)},
+		{"grep", "grep [flags] [-x] [-v] [-c] [-n] [-lazy [-cache bytes]] (regex | -load archivo) [archivo...]", "muestra las líneas que contienen una subcadena del lenguaje (-x: la línea completa)", cliCommand("grep", cmdGrep)},
+		{"difftest", "difftest [flags] [-k 6] [-n 1000] [-maxlen 20] [-seed 1] regex", "compara los autómatas con regexp de Go en cadenas cortas y aleatorias; sale con 1 si difieren", cliCommand("difftest", cmdDifftest)},
+		{"ambiguity", "ambiguity [flags] regex", "decide si alguna cadena tiene dos árboles de derivación y el grado (finita, polinomial o exponencial); sale con 1 si es ambigua", cliCommand("ambiguity", cmdAmbiguity)},
+		{"test", "test [-min hopcroft] [-syntax perl] [-v] suite.txt...", "ejecuta suites con cadenas +w (aceptar) y -w (rechazar); sale con 1 si algo falla", cliCommand("test", cmdTest)},
+		{"serve", "serve [-addr localhost:8080]", "playground web y API JSON", fatalCommand(runServe)},
+		{"repl", "repl [-min hopcroft] [-sigma a,b]", "consola interactiva", fatalCommand(runREPL)},
+		{"help", "help", "muestra esta ayuda", runHelp},
+	}
+}
+
+// findSubcommand retorna el subcomando con ese nombre, o nil si no existe.
+func findSubcommand(name string) *subcommand {
+	for i := range subcommands {
+		if subcommands[i].name == name {
+			return &subcommands[i]
+		}
+	}
+	return nil
+}
+
+// runHelp lista los subcomandos y las flags comunes.
+func runHelp([]string) int {
+	fmt.Println("Uso: proyecto1 <subcomando> [flags] args...")
+	fmt.Println("     proyecto1 [flags]   (procesa las líneas regex;cadenas de -in; ver proyecto1 -h)")
+	fmt.Println()
+	for _, cmd := range subcommands {
+		fmt.Printf("  %-10s %s\n", cmd.name, cmd.help)
+	}
+	fmt.Println()
+	fmt.Println("Flags comunes: -sigma a,b,c   -min " + minimizeAlgorithmNames + "   -load archivo.json|.jff   -syntax proyecto|perl|posix   -json")
+	fmt.Printf("Códigos de salida: %d éxito, %d veredicto negativo, %d uso inválido, %d error\n", exitOK, exitFalse, exitUsage, exitError)
+	return exitOK
+}
+
+// fatalCommand adapta los subcomandos que solo pueden fallar con un error (serve, repl).
+func fatalCommand(run func(args []string) error) func(args []string) int {
+	return func(args []string) int {
+		if err := run(args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+}
+
+// cliFlags son las flags comunes a los subcomandos del pipeline, ya interpretadas.
+type cliFlags struct {
+	sigma  []rune // nil = símbolos de la regex o del autómata
+	minAlg nfa.MinimizeAlgorithm
+	load   string
+	json   bool
+	syntax regex.Syntax
+	out    io.Writer
+}
+
+// cliRun ejecuta un subcomando con sus operandos y retorna el código de salida.
+type cliRun func(cf *cliFlags, args []string) (int, error)
+
+// usageError es un error en las flags o los operandos; el subcomando sale con exitUsage.
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+// errUsage crea un usageError con formato.
+func errUsage(format string, args ...any) error {
+	return &usageError{fmt.Sprintf(format, args...)}
+}
+
+// cliCommand adapta un subcomando del pipeline: setup registra sus flags propias en el FlagSet y retorna
+// la función que lo ejecuta. Se agregan las flags comunes (-sigma, -min, -load, -syntax, -json) y los errores se
+// escriben en stderr, o en stdout como {"error": "..."} con -json.
+func cliCommand(name string, setup func(fs *flag.FlagSet) cliRun) func(args []string) int {
+	return func(args []string) int {
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		sigmaSpec := fs.String("sigma", "", "alfabeto declarado, por ejemplo a,b,c (por defecto, los símbolos de la regex)")
+		minName := fs.String("min", "hopcroft", "algoritmo de minimización: "+minimizeAlgorithmNames)
+		load := fs.String("load", "", "cargar el autómata desde JSON o JFLAP (.jff) en lugar de una regex")
+		jsonOut := fs.Bool("json", false, "salida en JSON")
+		syntaxName := fs.String("syntax", "proyecto", "sintaxis de la regex: proyecto, perl o posix (regexp de Go)")
+		run := setup(fs)
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return exitOK
+			}
+			return exitUsage
+		}
+
+		cf := &cliFlags{load: *load, json: *jsonOut, out: os.Stdout}
+		var err error
+		if cf.minAlg, err = nfa.ParseMinimizeAlgorithm(*minName); err != nil {
+			err = errUsage("flag -min inválido: %v", err)
+		} else if cf.sigma, err = parseSigma(*sigmaSpec); err != nil {
+			err = errUsage("flag -sigma inválido: %v", err)
+		} else if cf.syntax, err = regex.ParseSyntax(*syntaxName); err != nil {
+			err = errUsage("flag -syntax inválido: %v", err)
+		}
+		code := exitOK
+		if err == nil {
+			code, err = run(cf, fs.Args())
+		}
+		if err == nil {
+			return code
+		}
+
+		var usage *usageError
+		code = exitError
+		if errors.As(err, &usage) {
+			code = exitUsage
+		}
+		if cf.json {
+			cf.emit(map[string]string{"error": err.Error()}, nil)
+		} else {
+			fmt.Fprintf(os.Stderr, "proyecto1 %s: %v\n", name, err)
+			if code == exitUsage {
+				fmt.Fprintf(os.Stderr, "uso: proyecto1 %s\n", findSubcommand(name).usage)
+			}
+		}
+		return code
+	}
+}
+
+// emit escribe el resultado: v como JSON con -json o, si no, el texto que produce text.
+func (cf *cliFlags) emit(v any, text func(w io.Writer)) {
+	if cf.json {
+		enc := json.NewEncoder(cf.out)
+		enc.SetIndent("", "  ")
+		enc.Encode(v)
+		return
+	}
+	text(cf.out)
+}
+
+// input compila el primer operando como regex o, con -load, carga el autómata del archivo.
+// Retorna además los operandos restantes.
+func (cf *cliFlags) input(args []string) (*compiled, []string, error) {
+	if cf.load != "" {
+		c, err := loadPipeline(cf.load, cf.sigma, cf.minAlg)
+		return c, args, err
+	}
+	if len(args) == 0 {
+		return nil, nil, errUsage("falta la regex (o -load archivo)")
+	}
+	r, err := cf.regex(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := compilePipeline(r, cf.sigma, cf.minAlg)
+	return c, args[1:], err
+}
+
+// regex retorna el operando en la sintaxis del proyecto, convirtiéndolo con regex.Import si -syntax es
+// perl o posix.
+func (cf *cliFlags) regex(arg string) (string, error) {
+	if cf.syntax == regex.NativeSyntax {
+		return arg, nil
+	}
+	n, err := regex.Import(arg, cf.syntax)
+	if err != nil {
+		return "", fmt.Errorf("error de importación: %v", err)
+	}
+	return n.String(), nil
+}
+
+// inputNFA es como input, pero de una regex solo construye el NFA (ver compileNFA).
+func (cf *cliFlags) inputNFA(args []string) (*compiled, []string, error) {
+	if cf.load != "" || len(args) == 0 {
+		return cf.input(args)
+	}
+	r, err := cf.regex(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := compileNFA(r, cf.sigma)
+	return c, args[1:], err
+}
+
+// noMoreArgs falla si quedan operandos sin usar.
+func noMoreArgs(args []string) error {
+	if len(args) > 0 {
+		return errUsage("operandos de más: %s", strings.Join(args, " "))
+	}
+	return nil
+}
+
+// cmdBuild muestra las etapas del pipeline (en JSON, con los autómatas como en /compile).
+func cmdBuild(fs *flag.FlagSet) cliRun {
+	return func(cf *cliFlags, args []string) (int, error) {
+		c, rest, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if err := noMoreArgs(rest); err != nil {
+			return exitUsage, err
+		}
+		cf.emit(newCompileResponse(c), func(w io.Writer) {
+			if c.Input != nil {
+				fmt.Fprintf(w, "Autómata cargado: %s\n", c.Original)
+			} else {
+				fmt.Fprintf(w, "Regex original: %s\n", c.Original)
+				fmt.Fprintf(w, "Expandida: %s\n", c.Expanded)
+				fmt.Fprintf(w, "Formateada: %s\n", c.Formatted)
+				fmt.Fprintf(w, "Postfija: %s\n", c.Postfix)
+				fmt.Fprintf(w, "AST: %s\n%s", c.AST, c.AST.Tree())
+			}
+			fmt.Fprintf(w, "Alfabeto: %s\n", config.FormatAlphabet(c.Alphabet))
+			fmt.Fprintf(w, "Métricas:\n")
+			c.Metrics().write(w, "  ")
+		})
+		return exitOK, nil
+	}
+}
+
+// cmdMatch evalúa cada cadena en los tres autómatas. El veredicto que decide el código de salida
+// es el del DFA minimizado.
+func cmdMatch(fs *flag.FlagSet) cliRun {
+	return func(cf *cliFlags, args []string) (int, error) {
+		c, words, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if len(words) == 0 {
+			return exitUsage, errUsage("faltan las cadenas a evaluar")
+		}
+		code := exitOK
+		results := []matchResult{}
+		for _, tok := range words {
+			res, err := c.match(config.ParseWord(tok), cf.sigma != nil)
+			switch {
+			case err != nil, !res.Agree():
+				code = exitError // Una discrepancia entre los autómatas es un error del proyecto, no un veredicto
+			case !res.Min && code == exitOK:
+				code = exitFalse
+			}
+			results = append(results, newMatchResult(res))
+		}
+		cf.emit(map[string]any{"results": results}, func(w io.Writer) {
+			for _, r := range results {
+				if r.Error != "" {
+					fmt.Fprintf(w, "%s: error: %s\n", config.FormatWord(r.Word), r.Error)
+					continue
+				}
+				fmt.Fprintf(w, "%s: %s (NFA %s%s, DFA %s, minDFA %s)\n", config.FormatWord(r.Word), yesNo(r.MinDFA), yesNo(r.NFA), setsNote(r.NFA, r.NFASets), yesNo(r.DFA), yesNo(r.MinDFA))
+			}
+		})
+		return code, nil
+	}
+}
+
+// cmdMinimize muestra el DFA minimizado con -min y, con -o, lo guarda (formato según la extensión).
+func cmdMinimize(fs *flag.FlagSet) cliRun {
+	outPath := fs.String("o", "", "guardar el DFA minimizado en este archivo (.json, .jff, .svg, .dot, ...)")
+	return func(cf *cliFlags, args []string) (int, error) {
+		c, rest, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if err := noMoreArgs(rest); err != nil {
+			return exitUsage, err
+		}
+		if *outPath != "" {
+			if err := saveAutomaton(c.MinDFA, nil, *outPath); err != nil {
+				return exitError, err
+			}
+		}
+		cf.emit(map[string]any{
+			"algorithm":     cf.minAlg.String(),
+			"states_before": len(c.DFA.States),
+			"states_after":  len(c.MinDFA.States),
+			"min_dfa":       nfa.DFAToJSON(c.MinDFA),
+		}, func(w io.Writer) {
+			fmt.Fprintf(w, "Algoritmo: %v\n", cf.minAlg)
+			fmt.Fprintf(w, "Estados: DFA %d, DFA minimizado %d\n", len(c.DFA.States), len(c.MinDFA.States))
+			graphviz.FormatTableDFA(w, c.MinDFA)
+			if *outPath != "" {
+				fmt.Fprintf(w, "Guardado: %s\n", *outPath)
+			}
+		})
+		return exitOK, nil
+	}
+}
+
+// cmdEquiv compara el lenguaje del primer operando con el de la regex del segundo.
+func cmdEquiv(fs *flag.FlagSet) cliRun {
+	return func(cf *cliFlags, args []string) (int, error) {
+		a, rest, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if len(rest) != 1 {
+			return exitUsage, errUsage("se esperaban dos operandos")
+		}
+		r, err := cf.regex(rest[0])
+		if err != nil {
+			return exitError, fmt.Errorf("%s: %v", rest[0], err)
+		}
+		b, err := compilePipeline(r, cf.sigma, cf.minAlg)
+		if err != nil {
+			return exitError, fmt.Errorf("%s: %v", rest[0], err)
+		}
+		resp := equivalence(a, b, cf.sigma)
+		cf.emit(resp, func(w io.Writer) {
+			if resp["equivalent"].(bool) {
+				fmt.Fprintf(w, "Equivalentes: L(%s) = L(%s)\n", a.Original, b.Original)
+				return
+			}
+			in, out := a.Original, b.Original
+			if resp["in"] == "regex2" {
+				in, out = out, in
+			}
+			fmt.Fprintf(w, "No equivalentes: %s pertenece a L(%s) pero no a L(%s)\n", config.FormatWord(resp["witness"].(string)), in, out)
+		})
+		if !resp["equivalent"].(bool) {
+			return exitFalse, nil
+		}
+		return exitOK, nil
+	}
+}
+
+// drawingExts son las extensiones que acepta render.
+var drawingExts = []string{".svg", ".png", ".dot", ".mmd", ".tex", ".md"}
+
+// cmdRender dibuja una etapa en el formato de la extensión de -o, o como SVG en stdout.
+func cmdRender(fs *flag.FlagSet) cliRun {
+	stage := fs.String("stage", "min", "etapa a dibujar: nfa, dfa, min o input")
+	outPath := fs.String("o", "", "archivo de salida ("+strings.Join(drawingExts, ", ")+"); sin -o, SVG en stdout")
+	return func(cf *cliFlags, args []string) (int, error) {
+		c, rest, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if err := noMoreArgs(rest); err != nil {
+			return exitUsage, err
+		}
+		a, err := c.stage(*stage)
+		if err != nil {
+			return exitUsage, errUsage("flag -stage inválido: %v", err)
+		}
+		if *outPath == "" {
+			if cf.json {
+				cf.emit(map[string]any{"stage": *stage, "states": stateCount(a), "svg": renderSVGString(a)}, nil)
+				return exitOK, nil
+			}
+			return exitOK, renderSVGTo(cf.out, a)
+		}
+		if ext := strings.ToLower(filepath.Ext(*outPath)); !containsString(drawingExts, ext) {
+			return exitUsage, errUsage("extensión %q no soportada por render (%s; para JSON o JFLAP use convert)", ext, strings.Join(drawingExts, ", "))
+		}
+		if err := saveAutomaton(a, nil, *outPath); err != nil {
+			return exitError, err
+		}
+		cf.emit(map[string]any{"stage": *stage, "states": stateCount(a), "path": *outPath}, func(w io.Writer) {
+			fmt.Fprintf(w, "Guardado: %s\n", *outPath)
+		})
+		return exitOK, nil
+	}
+}
+
+// renderSVGTo dibuja un autómata con el renderizador SVG nativo en w.
+func renderSVGTo(w io.Writer, a nfa.Automaton) error {
+	if d, ok := a.(*nfa.DFA); ok {
+		return graphviz.RenderSVGDFA(w, d)
+	}
+	return graphviz.RenderSVG(w, a.AsNFA())
+}
+
+// stateCount retorna la cantidad de estados de un NFA o DFA.
+func stateCount(a nfa.Automaton) int {
+	if d, ok := a.(*nfa.DFA); ok {
+		return len(d.States)
+	}
+	return len(a.AsNFA().States)
+}
+
+// cmdEnumerate lista las cadenas del lenguaje (del DFA minimizado) en orden canónico.
+func cmdEnumerate(fs *flag.FlagSet) cliRun {
+	limit := fs.Int("n", 20, "cantidad máxima de cadenas (0 = sin límite, requiere -maxlen)")
+	maxLen := fs.Int("maxlen", -1, "longitud máxima de las cadenas (-1 = sin límite)")
+	return func(cf *cliFlags, args []string) (int, error) {
+		if *limit <= 0 && *maxLen < 0 {
+			return exitUsage, errUsage("-n 0 requiere -maxlen: el lenguaje puede ser infinito")
+		}
+		c, rest, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if err := noMoreArgs(rest); err != nil {
+			return exitUsage, err
+		}
+		words, complete := nfa.Enumerate(c.MinDFA, *maxLen, *limit)
+		cf.emit(map[string]any{"words": words, "complete": complete}, func(w io.Writer) {
+			for _, word := range words {
+				fmt.Fprintln(w, config.FormatWord(word))
+			}
+			if !complete {
+				fmt.Fprintln(os.Stderr, "(el lenguaje tiene más cadenas; ajuste -n o -maxlen)")
+			}
+		})
+		if len(words) == 0 && complete {
+			return exitFalse, nil // Lenguaje vacío; sin complete solo faltan cadenas más largas
+		}
+		return exitOK, nil
+	}
+}
+
+// cmdConvert guarda una etapa en JSON o JFLAP. La etapa input conserva el autómata cargado con -load
+// (y sus coordenadas al escribir .jff); con una regex corresponde al NFA de Thompson.
+func cmdConvert(fs *flag.FlagSet) cliRun {
+	stage := fs.String("stage", "input", "etapa a guardar: input, nfa, dfa o min")
+	return func(cf *cliFlags, args []string) (int, error) {
+		c, rest, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if len(rest) != 1 {
+			return exitUsage, errUsage("falta el archivo de salida (o - para JSON en stdout)")
+		}
+		a, err := c.stage(*stage)
+		if err != nil {
+			return exitUsage, errUsage("flag -stage inválido: %v", err)
+		}
+		outPath := rest[0]
+		if outPath == "-" {
+			if d, ok := a.(*nfa.DFA); ok {
+				return exitOK, nfa.EncodeDFA(cf.out, d)
+			}
+			return exitOK, nfa.EncodeNFA(cf.out, a.AsNFA())
+		}
+		if ext := strings.ToLower(filepath.Ext(outPath)); ext != ".json" && ext != ".jff" {
+			return exitUsage, errUsage("extensión %q no soportada por convert (.json o .jff; para dibujos use render)", ext)
+		}
+		layout := c.Layout
+		if *stage != "input" {
+			layout = nil
+		}
+		if err := saveAutomaton(a, layout, outPath); err != nil {
+			return exitError, err
+		}
+		cf.emit(map[string]any{"stage": *stage, "states": stateCount(a), "path": outPath}, func(w io.Writer) {
+			fmt.Fprintf(w, "Guardado: %s\n", outPath)
+		})
+		return exitOK, nil
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// AlphabetDirective es el prefijo de la directiva de archivo que declara Σ para las líneas siguientes.
+// Ejemplo: "@alfabeto a,b,c". Una directiva vacía ("@alfabeto") vuelve a derivar Σ de cada regex.
+const AlphabetDirective = "@alfabeto"
+
+// ParseAlphabet interpreta una declaración de alfabeto como "a,b,c", "{a, b, c}" o "abc".
+// Las comas, espacios y llaves son separadores; cada otro carácter es un símbolo de Σ.
+// Retorna error si algún símbolo no es alfanumérico o es ε (ε no es un símbolo del alfabeto).
+func ParseAlphabet(spec string) ([]rune, error) {
+	spec = normalizeEpsilon(spec)
+	alphabet := []rune{}
+	for _, c := range spec {
+		switch {
+		case c == ',', c == '{', c == '}', c == ' ', c == '\t':
+			continue
+		case c == 'ε':
+			return nil, fmt.Errorf("ε no puede ser un símbolo del alfabeto")
+		case !IsAlphanumeric(c):
+			return nil, fmt.Errorf("símbolo %q inválido en el alfabeto (solo letras y dígitos)", c)
+		}
+		if !ContainsRune(alphabet, c) {
+			alphabet = append(alphabet, c)
+		}
+	}
+	if len(alphabet) == 0 {
+		return nil, fmt.Errorf("alfabeto vacío")
+	}
+	return alphabet, nil
+}
+
+// RegexAlphabet deriva el alfabeto de una regex formateada: sus símbolos alfanuméricos
+// distintos de ε, en orden de aparición.
+func RegexAlphabet(formatted string) []rune {
+	alphabet := []rune{}
+	for _, c := range formatted {
+		if IsAlphanumeric(c) && c != 'ε' && !ContainsRune(alphabet, c) {
+			alphabet = append(alphabet, c)
+		}
+	}
+	return alphabet
+}
+
+// FormatAlphabet retorna el alfabeto en notación de conjunto, por ejemplo "{a, b}".
+func FormatAlphabet(alphabet []rune) string {
+	syms := make([]string, 0, len(alphabet))
+	for _, c := range alphabet {
+		syms = append(syms, string(c))
+	}
+	return "{" + strings.Join(syms, ", ") + "}"
+}
+
+// ParseWord interpreta una cadena de prueba: "ε" (o su variante 𝜀) denota la cadena vacía.
+func ParseWord(tok string) string {
+	if normalizeEpsilon(tok) == "ε" {
+		return ""
+	}
+	return tok
+}
+
+// FormatWord es la inversa de ParseWord: escribe la cadena vacía como "ε".
+func FormatWord(w string) string {
+	if w == "" {
+		return "ε"
+	}
+	return w
+}
+
+// ValidateWord verifica que todos los símbolos de w pertenezcan al alfabeto.
+// El error indica el primer símbolo inválido y su posición (contando desde 1).
+func ValidateWord(w string, alphabet []rune) error {
+	pos := 0
+	for len(w) > 0 {
+		c, size := utf8.DecodeRuneInString(w)
+		w = w[size:]
+		pos++
+		if !ContainsRune(alphabet, c) {
+			return fmt.Errorf("el símbolo %q (posición %d) no pertenece a Σ = %s", c, pos, FormatAlphabet(alphabet))
+		}
+	}
+	return nil
+}
+
+// ValidateRegexAlphabet verifica que los símbolos de la regex formateada pertenezcan al alfabeto declarado.
+func ValidateRegexAlphabet(formatted string, alphabet []rune) error {
+	for _, c := range RegexAlphabet(formatted) {
+		if !ContainsRune(alphabet, c) {
+			return fmt.Errorf("la regex usa el símbolo %q, que no pertenece a Σ = %s", c, FormatAlphabet(alphabet))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import "strings"
+
+// Expectation es el veredicto esperado para una cadena de prueba.
+type Expectation int
+
+const (
+	NoExpectation Expectation = iota // Sin marca: solo se muestra el veredicto
+	ExpectAccept                     // "+w": w debe pertenecer al lenguaje
+	ExpectReject                     // "-w": w no debe pertenecer al lenguaje
+)
+
+// Accepts indica si la expectativa es de aceptación. Solo tiene sentido si e != NoExpectation.
+func (e Expectation) Accepts() bool {
+	return e == ExpectAccept
+}
+
+// Satisfied indica si el veredicto obtenido coincide con el esperado (siempre true sin expectativa).
+func (e Expectation) Satisfied(accepted bool) bool {
+	return e == NoExpectation || accepted == e.Accepts()
+}
+
+// TestWord es una cadena de prueba con su veredicto esperado.
+type TestWord struct {
+	Word   string
+	Expect Expectation
+}
+
+// ParseTestWord interpreta una cadena de prueba con marca opcional: "+w" espera aceptación, "-w" espera
+// rechazo y "w" no tiene expectativa. La marca se quita antes de aplicar ParseWord, así que "+ε" espera
+// que se acepte la cadena vacía. Para probar una cadena que empieza con + o -, se antepone una marca ("+-a").
+func ParseTestWord(tok string) TestWord {
+	switch {
+	case strings.HasPrefix(tok, "+"):
+		return TestWord{Word: ParseWord(tok[1:]), Expect: ExpectAccept}
+	case strings.HasPrefix(tok, "-"):
+		return TestWord{Word: ParseWord(tok[1:]), Expect: ExpectReject}
+	}
+	return TestWord{Word: ParseWord(tok)}
+}
+
+// ParseTestWords interpreta la lista de cadenas de una línea de entrada (separadas por coma), omitiendo
+// los elementos vacíos.
+func ParseTestWords(csv string) []TestWord {
+	var words []TestWord
+	for _, tok := range strings.Split(csv, ",") {
+		if tok = strings.TrimSpace(tok); tok != "" {
+			words = append(words, ParseTestWord(tok))
+		}
+	}
+	return words
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseMapping lee un archivo de mapeo símbolo → cadena, usado por homomorfismos y sustituciones.
+// Cada línea tiene la forma "a -> 01"; las líneas vacías y las que empiezan con '#' se ignoran.
+// El lado derecho "ε" (o vacío) representa la cadena vacía. El lado derecho se retorna sin
+// interpretar: puede ser una cadena (homomorfismo) o una regex (sustitución).
+func ParseMapping(r io.Reader) (map[rune]string, error) {
+	mapping := map[rune]string{}
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		raw := strings.TrimSpace(sc.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(raw, "->", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("línea %d: formato inválido, se esperaba 'a -> cadena', se encontró %q", lineNo, raw)
+		}
+		left := normalizeEpsilon(strings.TrimSpace(parts[0]))
+		right := normalizeEpsilon(strings.TrimSpace(parts[1]))
+
+		sym, size := utf8.DecodeRuneInString(left)
+		if size == 0 || size != len(left) || !IsAlphanumeric(sym) || sym == 'ε' {
+			return nil, fmt.Errorf("línea %d: el lado izquierdo debe ser un único símbolo del alfabeto, se encontró %q", lineNo, left)
+		}
+		if _, dup := mapping[sym]; dup {
+			return nil, fmt.Errorf("línea %d: el símbolo %q está definido más de una vez", lineNo, sym)
+		}
+		if right == "ε" {
+			right = ""
+		}
+		mapping[sym] = right
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("el archivo de mapeo no define ningún símbolo")
+	}
+	return mapping, nil
+}
@@ -0,0 +1,284 @@
+// /proyecto1/difftest.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"strings"
+
+	"proyecto1/config"
+	"proyecto1/nfa"
+	"proyecto1/regex"
+)
+
+// outsideSymbol es el símbolo fuera de Σ que se mezcla en las cadenas aleatorias de difftest, para
+// comprobar que ningún autómata acepta cadenas con símbolos ajenos al alfabeto.
+const outsideSymbol = '#'
+
+// maxExhaustive es la cantidad máxima de cadenas de la fase exhaustiva de difftest: con -syntax perl o posix
+// una clase de caracteres como [a-z] agrega decenas de símbolos a Σ, y |Σ|^k crece muy rápido.
+const maxExhaustive = 100_000
+
+// exhaustiveLen retorna la mayor longitud ≤ k tal que las cadenas de longitud 0..k sobre symbols símbolos
+// no pasen de maxExhaustive.
+func exhaustiveLen(symbols, k int) int {
+	total, level := 1, 1
+	for length := 1; length <= k; length++ {
+		level *= symbols
+		total += level
+		if total > maxExhaustive {
+			return length - 1
+		}
+	}
+	return k
+}
+
+// goTranslator traduce una regex de la sintaxis del proyecto (símbolos alfanuméricos, ε, |, . explícito,
+// *, +, ? y paréntesis) a la sintaxis de regexp de Go. Es un parser independiente de config y regex, para que
+// regexp sirva de oráculo de todo el pipeline:
+//
+//	alt    := concat ('|' concat)*
+//	concat := repeat ('.'? repeat)*
+//	repeat := atom ('*' | '+' | '?')*
+//	atom   := símbolo | 'ε' | '(' alt ')'
+type goTranslator struct {
+	in  []rune
+	pos int
+}
+
+// goPattern retorna el patrón de Go anclado (^...$) equivalente a la regex.
+func goPattern(r string) (string, error) {
+	t := &goTranslator{in: []rune(strings.ReplaceAll(r, "𝜀", "ε"))}
+	body, err := t.alt()
+	if err != nil {
+		return "", err
+	}
+	if t.pos < len(t.in) {
+		return "", fmt.Errorf("')' sin abrir en la posición %d", t.pos+1)
+	}
+	return "^(?:" + body + ")$", nil
+}
+
+func (t *goTranslator) peek() (rune, bool) {
+	if t.pos < len(t.in) {
+		return t.in[t.pos], true
+	}
+	return 0, false
+}
+
+func (t *goTranslator) alt() (string, error) {
+	parts := []string{}
+	for {
+		part, err := t.concat()
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+		if c, ok := t.peek(); !ok || c != '|' {
+			return strings.Join(parts, "|"), nil
+		}
+		t.pos++
+	}
+}
+
+func (t *goTranslator) concat() (string, error) {
+	var b strings.Builder
+	for {
+		c, ok := t.peek()
+		if !ok || c == '|' || c == ')' {
+			return b.String(), nil
+		}
+		if c == '.' && b.Len() > 0 {
+			t.pos++ // Concatenación explícita
+		}
+		rep, err := t.repeat()
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(rep)
+	}
+}
+
+// repeat agrupa el operando antes de cada operador, así que a** (válida en el proyecto) se traduce
+// como (?:(?:a)*)*, que Go sí acepta.
+func (t *goTranslator) repeat() (string, error) {
+	atom, err := t.atom()
+	if err != nil {
+		return "", err
+	}
+	for {
+		c, ok := t.peek()
+		if !ok || (c != '*' && c != '+' && c != '?') {
+			return atom, nil
+		}
+		t.pos++
+		atom = "(?:" + atom + ")" + string(c)
+	}
+}
+
+func (t *goTranslator) atom() (string, error) {
+	c, _ := t.peek()
+	t.pos++
+	switch {
+	case c == '(':
+		inner, err := t.alt()
+		if err != nil {
+			return "", err
+		}
+		if c, ok := t.peek(); !ok || c != ')' {
+			return "", fmt.Errorf("falta ')' para el '(' de la regex")
+		}
+		t.pos++
+		return "(?:" + inner + ")", nil
+	case c == 'ε':
+		return "(?:)", nil
+	case config.IsAlphanumeric(c):
+		return regexp.QuoteMeta(string(c)), nil
+	}
+	return "", fmt.Errorf("símbolo %q no soportado en la posición %d", c, t.pos)
+}
+
+// diffCase es una cadena con los veredictos del proyecto y el de regexp.
+type diffCase struct {
+	nfa.Verdicts
+	Go bool `json:"go"`
+}
+
+// agree indica si los autómatas del proyecto coinciden entre sí y con regexp.
+func (d diffCase) agree() bool { return d.Agree() && d.NFA == d.Go }
+
+// cmdDifftest compara el pipeline con regexp de Go: traduce la regex a un patrón de Go anclado (con
+// -syntax perl o posix, ancla la regex original) y evalúa todas las cadenas sobre Σ de longitud ≤ k y
+// n cadenas aleatorias (con algún símbolo fuera de Σ) en regexp.MatchString, el NFA (por conjuntos y por
+// bits), el DFA y el DFA minimizado. Sale con exitFalse si alguno no coincide o si el pipeline no compila
+// una regex que Go acepta.
+func cmdDifftest(fs *flag.FlagSet) cliRun {
+	maxLen := fs.Int("k", defaultVerifyLen, "longitud máxima de las cadenas exhaustivas")
+	random := fs.Int("n", 1000, "cantidad de cadenas aleatorias")
+	randomLen := fs.Int("maxlen", 20, "longitud máxima de las cadenas aleatorias")
+	seed := fs.Int64("seed", 1, "semilla de las cadenas aleatorias")
+	show := fs.Int("show", 10, "cantidad máxima de discrepancias a mostrar")
+	return func(cf *cliFlags, args []string) (int, error) {
+		if cf.load != "" {
+			return exitUsage, errUsage("difftest necesita una regex (no admite -load)")
+		}
+		if *maxLen < 0 || *random < 0 || *randomLen < 0 {
+			return exitUsage, errUsage("-k, -n y -maxlen deben ser mayores o iguales que 0")
+		}
+		if len(args) == 0 {
+			return exitUsage, errUsage("falta la regex")
+		}
+		if err := noMoreArgs(args[1:]); err != nil {
+			return exitUsage, err
+		}
+		// Con -syntax perl o posix, el oráculo es la regex original: se verifica también regex.Import.
+		// Las construcciones que Import no admite son un error, no una discrepancia.
+		if _, err := cf.regex(args[0]); err != nil {
+			return exitError, err
+		}
+		var pattern string
+		compile := regexp.Compile
+		switch cf.syntax {
+		case regex.NativeSyntax:
+			p, err := goPattern(args[0])
+			if err != nil {
+				return exitError, fmt.Errorf("no se puede traducir a Go: %v", err)
+			}
+			pattern = p
+		case regex.POSIXSyntax:
+			compile = regexp.CompilePOSIX
+			fallthrough
+		default:
+			pattern = "^(?:" + args[0] + ")$"
+		}
+		re, err := compile(pattern)
+		if err != nil {
+			return exitError, fmt.Errorf("Go no acepta la traducción %s: %v", pattern, err)
+		}
+		c, _, err := cf.input(args)
+		if err != nil {
+			// La regex es válida para el traductor y para Go: que el pipeline la rechace también es una discrepancia
+			cf.emit(map[string]any{"pattern": pattern, "agree": false, "pipeline_error": err.Error()}, func(w io.Writer) {
+				fmt.Fprintf(w, "Patrón de Go: %s\n", pattern)
+				fmt.Fprintf(w, "FALLA: regexp acepta la regex, pero el pipeline la rechaza: %v\n", err)
+			})
+			return exitFalse, nil
+		}
+
+		checked := 0
+		failures := []diffCase{}
+		check := func(w string) {
+			checked++
+			d := diffCase{Verdicts: nfa.CrossCheckWith(c.Sim, c.NFA, c.DFA, c.MinDFA, w), Go: re.MatchString(w)}
+			if !d.agree() {
+				failures = append(failures, d)
+			}
+		}
+
+		// Exhaustivas: todas las cadenas de longitud 0..k en orden canónico, acortando k si son demasiadas
+		k := exhaustiveLen(len(c.Alphabet), *maxLen)
+		word := make([]rune, 0, k)
+		for length := 0; length <= k; length++ {
+			var level func()
+			level = func() {
+				if len(word) == length {
+					check(string(word))
+					return
+				}
+				for _, sym := range c.Alphabet {
+					word = append(word, sym)
+					level()
+					word = word[:len(word)-1]
+				}
+			}
+			level()
+		}
+
+		// Aleatorias: símbolos de Σ y, con probabilidad baja, outsideSymbol
+		rng := rand.New(rand.NewSource(*seed))
+		symbols := append(append([]rune{}, c.Alphabet...), outsideSymbol)
+		for i := 0; i < *random; i++ {
+			word = word[:0]
+			for n := rng.Intn(*randomLen + 1); n > 0; n-- {
+				if len(c.Alphabet) == 0 || rng.Intn(20) == 0 {
+					word = append(word, symbols[len(symbols)-1])
+				} else {
+					word = append(word, symbols[rng.Intn(len(c.Alphabet))])
+				}
+			}
+			check(string(word))
+		}
+
+		resp := map[string]any{"pattern": pattern, "k": k, "checked": checked, "disagreements": len(failures), "agree": len(failures) == 0}
+		shown := failures
+		if len(shown) > *show {
+			shown = shown[:*show]
+		}
+		resp["cases"] = shown
+		cf.emit(resp, func(w io.Writer) {
+			fmt.Fprintf(w, "Patrón de Go: %s\n", pattern)
+			if k < *maxLen {
+				fmt.Fprintf(w, "Cadenas exhaustivas hasta longitud %d (no %d): con |Σ| = %d serían más de %d\n", k, *maxLen, len(c.Alphabet), maxExhaustive)
+			}
+			for _, d := range shown {
+				fmt.Fprintf(w, "FALLA %s (Go %s, NFA %s, NFA por conjuntos %s, DFA %s, minDFA %s)\n", config.FormatWord(d.Word),
+					yesNo(d.Go), yesNo(d.NFA), yesNo(d.Sets), yesNo(d.DFA), yesNo(d.MinDFA))
+			}
+			if len(failures) > len(shown) {
+				fmt.Fprintf(w, "... y %d discrepancias más\n", len(failures)-len(shown))
+			}
+			if len(failures) == 0 {
+				fmt.Fprintf(w, "regexp y los autómatas coinciden en las %d cadenas evaluadas\n", checked)
+			} else {
+				fmt.Fprintf(w, "%d discrepancias en %d cadenas evaluadas\n", len(failures), checked)
+			}
+		})
+		if len(failures) > 0 {
+			return exitFalse, nil
+		}
+		return exitOK, nil
+	}
+}
@@ -0,0 +1,215 @@
+// /proyecto1/export.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"proyecto1/graphviz"
+	"proyecto1/jflap"
+	"proyecto1/nfa"
+	"proyecto1/thompson"
+)
+
+// textFormat es un formato de texto en el que se pueden exportar los autómatas (flag -format).
+type textFormat struct {
+	name     string // Nombre en los mensajes
+	saved    string // "guardado" o "guardada", según el género de name
+	ext      string
+	writeNFA func(n *thompson.NFA, path string) error
+	writeDFA func(dfa *nfa.DFA, path string) error
+}
+
+// textFormats asocia cada valor de -format con sus escritores del paquete graphviz.
+var textFormats = map[string]textFormat{
+	"dot":     {"DOT", "guardado", ".dot", graphviz.WriteDOT, graphviz.WriteDOTDFA},
+	"mermaid": {"Mermaid", "guardado", ".mmd", graphviz.WriteMermaid, graphviz.WriteMermaidDFA},
+	"tikz":    {"TikZ", "guardado", ".tex", graphviz.WriteTikZ, graphviz.WriteTikZDFA},
+	"table":   {"Tabla δ", "guardada", ".md", graphviz.WriteTable, graphviz.WriteTableDFA},
+}
+
+// textFormatNames retorna los nombres de los formatos de texto, ordenados.
+func textFormatNames() []string {
+	names := make([]string, 0, len(textFormats))
+	for name := range textFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseFormats interpreta el valor de -format: uno o más formatos separados por coma.
+func parseFormats(spec string) ([]string, error) {
+	var formats []string
+	for _, tok := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(tok))
+		if name == "" {
+			continue
+		}
+		if _, ok := textFormats[name]; !ok {
+			return nil, fmt.Errorf("formato desconocido %q (disponibles: %s)", name, strings.Join(textFormatNames(), ", "))
+		}
+		if !containsString(formats, name) {
+			formats = append(formats, name)
+		}
+	}
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("no se indicó ningún formato")
+	}
+	return formats, nil
+}
+
+// containsString indica si s está en list.
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// outputOptions agrupa los directorios de salida y los formatos adicionales a generar.
+type outputOptions struct {
+	dotDir, pngDir string
+	formats        []string     // Formatos de texto (ver textFormats)
+	json           bool         // Guardar también cada etapa en JSON junto a los archivos DOT
+	jff            bool         // Guardar también cada etapa en formato JFLAP (.jff)
+	svg            bool         // Dibujar con el renderizador SVG nativo en lugar de Graphviz
+	layout         jflap.Layout // Coordenadas del autómata cargado, para su etapa en .jff
+}
+
+// exportStage guarda una etapa (NFA o DFA) con el nombre base en cada formato de texto elegido,
+// genera su imagen y, si se pidió, la guarda en JSON y JFLAP (con las coordenadas de layout).
+// what distingue la etapa en los mensajes (por ejemplo " DFA"). Los errores se informan en logOut.
+// Si ctx se cancela, se interrumpe Graphviz.
+func exportStage(ctx context.Context, logOut *log.Logger, what, base string, a nfa.Automaton, layout jflap.Layout, out outputOptions) {
+	n, isNFA := a.(*thompson.NFA)
+	d, _ := a.(*nfa.DFA)
+
+	dotPath := ""
+	for _, name := range out.formats {
+		format := textFormats[name]
+		path := filepath.Join(out.dotDir, base+format.ext)
+		var err error
+		if isNFA {
+			err = format.writeNFA(n, path)
+		} else {
+			err = format.writeDFA(d, path)
+		}
+		if err != nil {
+			logOut.Printf("  Error %s%s: %v\n", format.name, what, err)
+			continue
+		}
+		logOut.Printf("  %s%s %s: %s\n", format.name, what, format.saved, path)
+		if name == "dot" {
+			dotPath = path
+		}
+	}
+
+	// Sin archivo DOT no hay nada que pasarle a Graphviz: se dibuja directamente el SVG
+	renderImage(ctx, logOut, what, dotPath, filepath.Join(out.pngDir, base+".png"), out.svg || dotPath == "",
+		func(path string) error {
+			if isNFA {
+				return graphviz.WriteSVG(n, path)
+			}
+			return graphviz.WriteSVGDFA(d, path)
+		})
+
+	if out.json {
+		if isNFA {
+			writeNFAJSON(logOut, n, filepath.Join(out.dotDir, base+".json"))
+		} else {
+			writeDFAJSON(logOut, d, filepath.Join(out.dotDir, base+".json"))
+		}
+	}
+	if out.jff {
+		if isNFA {
+			writeNFAJFF(logOut, n, layout, filepath.Join(out.dotDir, base+".jff"))
+		} else {
+			writeDFAJFF(logOut, d, layout, filepath.Join(out.dotDir, base+".jff"))
+		}
+	}
+}
+
+// renderImage genera la imagen de una etapa a partir de su archivo DOT: un PNG con Graphviz o, si se
+// pidió el renderizador nativo (native) o dot falla, un SVG escrito con writeSVG junto a pngPath.
+// what distingue la etapa en los mensajes (por ejemplo " DFA").
+func renderImage(ctx context.Context, logOut *log.Logger, what, dotPath, pngPath string, native bool, writeSVG func(path string) error) {
+	if !native {
+		err := graphviz.GeneratePNGFromDotContext(ctx, dotPath, pngPath)
+		if err == nil {
+			logOut.Printf("  PNG%s guardado: %s\n", what, pngPath)
+			return
+		}
+		logOut.Printf("  Error PNG%s (¿está instalado Graphviz?): %v; se usa el renderizador SVG nativo\n", what, err)
+	}
+	svgPath := strings.TrimSuffix(pngPath, filepath.Ext(pngPath)) + ".svg"
+	if err := writeSVG(svgPath); err != nil {
+		logOut.Printf("  Error SVG%s: %v\n", what, err)
+		return
+	}
+	logOut.Printf("  SVG%s guardado: %s\n", what, svgPath)
+}
+
+// saveAutomaton guarda un NFA o DFA en el formato que indica la extensión del archivo: .json, .jff (con las
+// coordenadas de layout), .svg (renderizador nativo), .png (con Graphviz) o la de un formato de texto.
+func saveAutomaton(a nfa.Automaton, layout jflap.Layout, path string) error {
+	n, isNFA := a.(*thompson.NFA)
+	d, _ := a.(*nfa.DFA)
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".json", ".jff":
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case ext == ".json" && isNFA:
+			err = nfa.EncodeNFA(f, n)
+		case ext == ".json":
+			err = nfa.EncodeDFA(f, d)
+		case isNFA:
+			err = jflap.WriteNFA(f, n, layout)
+		default:
+			err = jflap.WriteDFA(f, d, layout)
+		}
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	case ".svg":
+		if isNFA {
+			return graphviz.WriteSVG(n, path)
+		}
+		return graphviz.WriteSVGDFA(d, path)
+	case ".png":
+		// El DOT intermedio se escribe junto al PNG y se descarta
+		dotPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".tmp.dot"
+		defer os.Remove(dotPath)
+		var err error
+		if isNFA {
+			err = graphviz.WriteDOT(n, dotPath)
+		} else {
+			err = graphviz.WriteDOTDFA(d, dotPath)
+		}
+		if err != nil {
+			return err
+		}
+		return graphviz.GeneratePNGFromDot(dotPath, path)
+	}
+	for _, format := range textFormats {
+		if format.ext == ext {
+			if isNFA {
+				return format.writeNFA(n, path)
+			}
+			return format.writeDFA(d, path)
+		}
+	}
+	return fmt.Errorf("extensión %q no soportada (.json, .jff, .svg, .png, .dot, .mmd, .tex o .md)", ext)
+}
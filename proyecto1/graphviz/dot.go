@@ -3,12 +3,15 @@
 package graphviz
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"proyecto1/nfa"
 	"proyecto1/thompson"
 	"sort"
+	"strings"
 )
 
 // WriteDOT escribe la representación DOT de un NFA en la ruta especificada.
@@ -47,10 +50,16 @@ func WriteDOT(nfa *thompson.NFA, path string) error {
 		fmt.Fprintf(f, "  q%d;\n", id)
 	}
 
-	// Aristas (transiciones, ordenadas para consistencia)
+	// Aristas (transiciones, ordenadas por estado y símbolo para consistencia)
 	for _, id := range ids {
 		s := idToState[id]
-		for label, outs := range s.Trans {
+		labels := make([]rune, 0, len(s.Trans))
+		for label := range s.Trans {
+			labels = append(labels, label)
+		}
+		sort.Slice(labels, func(i, j int) bool { return labels[i] < labels[j] })
+		for _, label := range labels {
+			outs := s.Trans[label]
 			lab := string(label)
 			if label == thompson.Epsilon {
 				lab = "ε"
@@ -75,29 +84,15 @@ func WriteDOTDFA(dfa *nfa.DFA, path string) error {
 	defer f.Close()
 
 	// Asignar letras a subconjuntos para mayor legibilidad
-	letters := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
-	subsetNames := map[string]string{}
-	for i, state := range dfa.States {
-		if i < len(letters) {
-			subsetNames[state] = string(letters[i])
-		} else {
-			// Si hay más estados que letras, usar q0, q1, etc.
-			subsetNames[state] = fmt.Sprintf("q%d", i-len(letters))
-		}
-	}
+	subsetNames := dfaStateNames(dfa)
 
 	// Comentarios con definiciones de los subconjuntos
 	fmt.Fprintln(f, "// Subconjuntos DFA:")
-	for state, letra := range subsetNames {
-		fmt.Fprintf(f, "// %s = %s\n", letra, state)
+	for _, state := range dfa.States {
+		fmt.Fprintf(f, "// %s = %s\n", subsetNames[state], state)
 	}
 
-	// Verificar si el estado inicial existe en el mapa
-	startName, ok := subsetNames[dfa.Start]
-	if !ok {
-		startName = "q0"
-		subsetNames[dfa.Start] = startName
-	}
+	startName := subsetNames[dfa.Start]
 
 	// Encabezado del grafo DOT
 	fmt.Fprintln(f, "digraph DFA {")
@@ -106,9 +101,11 @@ func WriteDOTDFA(dfa *nfa.DFA, path string) error {
 	fmt.Fprintf(f, "  s [shape=point];\n")
 	fmt.Fprintf(f, "  s -> %s;\n", startName)
 
-	// Estados de aceptación con doble círculo
-	for state := range dfa.Accepting {
-		fmt.Fprintf(f, "  %s [shape=doublecircle];\n", subsetNames[state])
+	// Estados de aceptación con doble círculo (en el orden de dfa.States para una salida estable)
+	for _, state := range dfa.States {
+		if dfa.Accepting[state] {
+			fmt.Fprintf(f, "  %s [shape=doublecircle];\n", subsetNames[state])
+		}
 	}
 
 	// Estados normales
@@ -118,9 +115,14 @@ func WriteDOTDFA(dfa *nfa.DFA, path string) error {
 		}
 	}
 
-	// Transiciones entre estados
-	for from, trans := range dfa.Transitions {
-		for sym, to := range trans {
+	// Transiciones entre estados (por estado y luego por símbolo del alfabeto)
+	for _, from := range dfa.States {
+		trans := dfa.Transitions[from]
+		for _, sym := range dfa.Alphabet {
+			to, ok := trans[sym]
+			if !ok {
+				continue
+			}
 			fromName := subsetNames[from]
 			toName := subsetNames[to]
 			fmt.Fprintf(f, "  %s -> %s [label=\"%c\"];\n", fromName, toName, sym)
@@ -135,8 +137,21 @@ func WriteDOTDFA(dfa *nfa.DFA, path string) error {
 // dotPath: ruta al archivo DOT de entrada.
 // pngPath: ruta al archivo PNG de salida.
 func GeneratePNGFromDot(dotPath, pngPath string) error {
-	cmd := exec.Command("dot", "-Tpng", dotPath, "-o", pngPath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return GeneratePNGFromDotContext(context.Background(), dotPath, pngPath)
+}
+
+// GeneratePNGFromDotContext es GeneratePNGFromDot con un contexto: si se cancela, se termina el proceso dot.
+// Los mensajes de dot se incluyen en el error en lugar de escribirse en la consola, para que no se mezclen
+// cuando se generan varias imágenes en paralelo.
+func GeneratePNGFromDotContext(ctx context.Context, dotPath, pngPath string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "dot", "-Tpng", dotPath, "-o", pngPath)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
 }
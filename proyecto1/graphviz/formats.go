@@ -0,0 +1,300 @@
+// Package graphviz proporciona funciones para generar archivos DOT de Graphviz,
+// incluyendo la exportación a Mermaid, TikZ (biblioteca automata de LaTeX) y tablas de transición.
+package graphviz
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"proyecto1/nfa"
+	"proyecto1/thompson"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// writeFile crea el archivo path y escribe en él con write.
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteMermaid escribe el NFA como un stateDiagram de Mermaid en la ruta especificada.
+func WriteMermaid(n *thompson.NFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return renderMermaid(w, nfaGraph(n)) })
+}
+
+// WriteMermaidDFA escribe el DFA como un stateDiagram de Mermaid (estados nombrados como en WriteDOTDFA).
+func WriteMermaidDFA(dfa *nfa.DFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return renderMermaid(w, dfaGraph(dfa)) })
+}
+
+// renderMermaid escribe el diagrama: [*] apunta al estado inicial y los estados de aceptación apuntan a [*].
+func renderMermaid(w io.Writer, g *graph) error {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	b.WriteString("    direction LR\n")
+	for _, node := range g.nodes {
+		if node.title != node.label {
+			fmt.Fprintf(&b, "    %s : %s\n", node.label, node.title)
+		} else {
+			fmt.Fprintf(&b, "    %s\n", node.label)
+		}
+	}
+	if len(g.nodes) > 0 {
+		fmt.Fprintf(&b, "    [*] --> %s\n", g.nodes[g.start].label)
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "    %s --> %s : %s\n", g.nodes[e.from].label, g.nodes[e.to].label, e.label)
+	}
+	for _, node := range g.nodes {
+		if node.accepting {
+			fmt.Fprintf(&b, "    %s --> [*]\n", node.label)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteTikZ escribe el NFA como código TikZ (biblioteca automata) en la ruta especificada.
+// Las posiciones de los estados salen del mismo dibujo por capas que WriteSVG.
+func WriteTikZ(n *thompson.NFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return renderTikZ(w, nfaGraph(n)) })
+}
+
+// WriteTikZDFA escribe el DFA como código TikZ (biblioteca automata) en la ruta especificada.
+func WriteTikZDFA(dfa *nfa.DFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return renderTikZ(w, dfaGraph(dfa)) })
+}
+
+// tikzScale convierte píxeles del dibujo por capas a centímetros (una capa ≈ 2.2 cm).
+const tikzScale = 2.2 / layerGap
+
+// renderTikZ escribe un tikzpicture listo para incluir en un documento con
+// \usetikzlibrary{automata, positioning, arrows}.
+func renderTikZ(w io.Writer, g *graph) error {
+	d := layoutGraph(g)
+	pairs := map[[2]int]bool{}
+	for _, e := range g.edges {
+		pairs[[2]int{e.from, e.to}] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("% Requiere \\usepackage{tikz} y \\usetikzlibrary{automata, positioning, arrows}\n")
+	b.WriteString("\\begin{tikzpicture}[shorten >=1pt, auto, >=stealth, initial text=]\n")
+	for i, node := range g.nodes {
+		opts := []string{"state"}
+		if i == g.start {
+			opts = append(opts, "initial")
+		}
+		if node.accepting {
+			opts = append(opts, "accepting")
+		}
+		p := d.pos[i]
+		fmt.Fprintf(&b, "  \\node[%s] (s%d) at (%.2f, %.2f) {%s};\n",
+			strings.Join(opts, ", "), i, p.x*tikzScale, -p.y*tikzScale, tikzStateLabel(node.label))
+	}
+	if len(g.edges) > 0 {
+		b.WriteString("  \\path[->]\n")
+		for i, e := range g.edges {
+			label := tikzEdgeLabel(e.label)
+			switch {
+			case e.from == e.to:
+				fmt.Fprintf(&b, "    (s%d) edge [loop above] node {%s} ()", e.from, label)
+			case pairs[[2]int{e.to, e.from}] || len(d.paths[i]) > 2:
+				// Aristas opuestas o que saltan capas: curvas para no tapar otros estados
+				fmt.Fprintf(&b, "    (s%d) edge [bend left] node {%s} (s%d)", e.from, label, e.to)
+			default:
+				fmt.Fprintf(&b, "    (s%d) edge node {%s} (s%d)", e.from, label, e.to)
+			}
+			if i == len(g.edges)-1 {
+				b.WriteString(";\n")
+			} else {
+				b.WriteString("\n")
+			}
+		}
+	}
+	b.WriteString("\\end{tikzpicture}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tikzStateLabel escribe el nombre de un estado en modo matemático: q12 → $q_{12}$, A → $A$.
+func tikzStateLabel(label string) string {
+	if strings.HasPrefix(label, "q") && len(label) > 1 {
+		return fmt.Sprintf("$q_{%s}$", label[1:])
+	}
+	return "$" + tikzEscape(label) + "$"
+}
+
+// tikzEdgeLabel escribe las etiquetas de una arista; ε se escribe como \varepsilon.
+func tikzEdgeLabel(label string) string {
+	parts := strings.Split(label, ",")
+	for i, p := range parts {
+		if p == string(thompson.Epsilon) {
+			parts[i] = "\\varepsilon"
+		} else {
+			parts[i] = tikzEscape(p)
+		}
+	}
+	return "$" + strings.Join(parts, ",") + "$"
+}
+
+// tikzEscape escapa los caracteres especiales de LaTeX.
+func tikzEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '#', '$', '%', '&', '_', '{', '}':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '\\':
+			b.WriteString("\\backslash{}")
+		case '~', '^':
+			fmt.Fprintf(&b, "\\%c{}", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// WriteTable escribe la tabla de transiciones δ del NFA en la ruta especificada (ver FormatTable).
+func WriteTable(n *thompson.NFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return FormatTable(w, n) })
+}
+
+// WriteTableDFA escribe la tabla de transiciones δ del DFA en la ruta especificada (ver FormatTableDFA).
+func WriteTableDFA(dfa *nfa.DFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return FormatTableDFA(w, dfa) })
+}
+
+// FormatTable escribe la tabla δ del NFA como tabla Markdown con columnas alineadas: una fila por
+// estado (→ marca el inicial y * el de aceptación) y una columna por símbolo, más ε si hay
+// transiciones epsilon. Cada celda es el conjunto de destinos, o ∅.
+func FormatTable(w io.Writer, n *thompson.NFA) error {
+	states := append([]*thompson.State{}, n.States...)
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+	symbols := nfa.Alphabet(n)
+	hasEpsilon := false
+	for _, s := range states {
+		if len(s.Trans[thompson.Epsilon]) > 0 {
+			hasEpsilon = true
+		}
+	}
+	if hasEpsilon {
+		symbols = append(symbols, thompson.Epsilon)
+	}
+
+	rows := [][]string{{"δ"}}
+	for _, sym := range symbols {
+		rows[0] = append(rows[0], string(sym))
+	}
+	for _, s := range states {
+		row := []string{markState(fmt.Sprintf("q%d", s.ID), s == n.Start, s == n.Accept)}
+		for _, sym := range symbols {
+			targets := make([]int, 0, len(s.Trans[sym]))
+			for _, t := range s.Trans[sym] {
+				targets = append(targets, t.ID)
+			}
+			sort.Ints(targets)
+			cell := "∅"
+			if len(targets) > 0 {
+				names := make([]string, len(targets))
+				for i, id := range targets {
+					names[i] = fmt.Sprintf("q%d", id)
+				}
+				cell = "{" + strings.Join(names, ", ") + "}"
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+	_, err := io.WriteString(w, alignTable(rows))
+	return err
+}
+
+// FormatTableDFA escribe la tabla δ del DFA como tabla Markdown con columnas alineadas, con los
+// estados nombrados como en WriteDOTDFA (→ inicial, * aceptación, ∅ sin transición), seguida de
+// la lista de subconjuntos que representa cada nombre.
+func FormatTableDFA(w io.Writer, dfa *nfa.DFA) error {
+	names := dfaStateNames(dfa)
+	rows := [][]string{{"δ"}}
+	for _, sym := range dfa.Alphabet {
+		rows[0] = append(rows[0], string(sym))
+	}
+	for _, state := range dfa.States {
+		row := []string{markState(names[state], state == dfa.Start, dfa.Accepting[state])}
+		for _, sym := range dfa.Alphabet {
+			cell := "∅"
+			if to, ok := dfa.Transitions[state][sym]; ok {
+				cell = names[to]
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	var b strings.Builder
+	b.WriteString(alignTable(rows))
+	b.WriteString("\n")
+	for _, state := range dfa.States {
+		fmt.Fprintf(&b, "- %s = %s\n", names[state], state)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markState antepone al nombre del estado → si es inicial y * si es de aceptación.
+func markState(name string, start, accepting bool) string {
+	mark := ""
+	if start {
+		mark += "→"
+	}
+	if accepting {
+		mark += "*"
+	}
+	return fmt.Sprintf("%-2s %s", mark, name)
+}
+
+// alignTable da formato de tabla Markdown a las filas (la primera es el encabezado), rellenando
+// cada columna hasta el ancho de su celda más larga para que también se lea como texto plano.
+func alignTable(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	pad := func(cell string, width int) string {
+		return cell + strings.Repeat(" ", width-utf8.RuneCountInString(cell))
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i, cell := range row {
+			b.WriteString(" " + pad(cell, widths[i]) + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(rows[0])
+	b.WriteString("|")
+	for _, width := range widths {
+		b.WriteString(strings.Repeat("-", width+2) + "|")
+	}
+	b.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return b.String()
+}
@@ -0,0 +1,359 @@
+// Package graphviz proporciona funciones para generar archivos DOT de Graphviz,
+// incluyendo un algoritmo de dibujo por capas (estilo Sugiyama) que no depende del binario dot.
+package graphviz
+
+import (
+	"fmt"
+	"proyecto1/nfa"
+	"proyecto1/thompson"
+	"sort"
+)
+
+// graph es la representación común de un NFA o DFA para dibujarlo.
+type graph struct {
+	nodes []gnode
+	start int
+	edges []gedge // Aristas entre pares de estados (las etiquetas paralelas se agrupan)
+}
+
+// gnode es un estado del grafo.
+type gnode struct {
+	label     string // Texto dentro del círculo
+	title     string // Nombre completo del estado (subconjunto en los DFA)
+	accepting bool
+}
+
+// gedge es una arista from → to con todas las etiquetas que comparten ese par de estados.
+type gedge struct {
+	from, to int
+	label    string
+}
+
+// graphBuilder agrupa las etiquetas de las transiciones paralelas en una sola arista.
+type graphBuilder struct {
+	g     *graph
+	index map[[2]int]int
+}
+
+func (b *graphBuilder) addEdge(from, to int, label string) {
+	key := [2]int{from, to}
+	if i, ok := b.index[key]; ok {
+		b.g.edges[i].label += "," + label
+		return
+	}
+	b.index[key] = len(b.g.edges)
+	b.g.edges = append(b.g.edges, gedge{from: from, to: to, label: label})
+}
+
+// nfaGraph construye el grafo de un NFA (estados ordenados por ID, nombrados q<ID> como en WriteDOT).
+func nfaGraph(n *thompson.NFA) *graph {
+	states := append([]*thompson.State{}, n.States...)
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+	index := make(map[*thompson.State]int, len(states))
+	b := &graphBuilder{g: &graph{}, index: map[[2]int]int{}}
+	for i, s := range states {
+		index[s] = i
+		name := fmt.Sprintf("q%d", s.ID)
+		b.g.nodes = append(b.g.nodes, gnode{label: name, title: name, accepting: s == n.Accept})
+	}
+	b.g.start = index[n.Start]
+	for _, s := range states {
+		labels := make([]rune, 0, len(s.Trans))
+		for label := range s.Trans {
+			labels = append(labels, label)
+		}
+		sort.Slice(labels, func(i, j int) bool { return labels[i] < labels[j] })
+		for _, label := range labels {
+			for _, t := range s.Trans[label] {
+				b.addEdge(index[s], index[t], string(label))
+			}
+		}
+	}
+	return b.g
+}
+
+// dfaGraph construye el grafo de un DFA con los mismos nombres de estados que WriteDOTDFA.
+func dfaGraph(dfa *nfa.DFA) *graph {
+	names := dfaStateNames(dfa)
+	index := make(map[string]int, len(dfa.States))
+	b := &graphBuilder{g: &graph{}, index: map[[2]int]int{}}
+	for i, state := range dfa.States {
+		index[state] = i
+		b.g.nodes = append(b.g.nodes, gnode{label: names[state], title: state, accepting: dfa.Accepting[state]})
+	}
+	b.g.start = index[dfa.Start]
+	for _, from := range dfa.States {
+		for _, sym := range dfa.Alphabet {
+			if to, ok := dfa.Transitions[from][sym]; ok {
+				b.addEdge(index[from], index[to], string(sym))
+			}
+		}
+	}
+	return b.g
+}
+
+// Medidas del dibujo (en píxeles).
+const (
+	nodeRadius = 18.0
+	layerGap   = 110.0 // Distancia horizontal entre capas
+	rowGap     = 70.0  // Distancia vertical entre nodos de una misma capa
+	margin     = 30.0
+	loopSpace  = 45.0 // Espacio reservado arriba para los lazos
+	startSpace = 40.0 // Espacio reservado a la izquierda para la flecha inicial
+)
+
+// point es un punto del plano.
+type point struct{ x, y float64 }
+
+// drawing es el resultado del algoritmo de dibujo: la posición de cada estado y el recorrido
+// de cada arista (de centro a centro, pasando por los nodos ficticios de las capas intermedias).
+type drawing struct {
+	pos           []point
+	paths         [][]point // nil para los lazos (from == to)
+	width, height float64
+}
+
+// layoutGraph dibuja el grafo por capas de izquierda a derecha (como rankdir=LR):
+//  1. Se invierten las aristas de retroceso de un DFS desde el estado inicial para obtener un DAG.
+//  2. Cada estado recibe como capa la longitud del camino más largo que llega a él.
+//  3. Las aristas que saltan capas se dividen con nodos ficticios, uno por capa intermedia.
+//  4. El orden dentro de cada capa se ajusta con barridos de baricentro para reducir cruces.
+//  5. Las capas se ubican en columnas y sus nodos se centran verticalmente.
+func layoutGraph(g *graph) *drawing {
+	n := len(g.nodes)
+
+	// 1. Aristas de retroceso (las que cierran un ciclo en el DFS)
+	out := make([][]int, n)
+	for i, e := range g.edges {
+		if e.from != e.to {
+			out[e.from] = append(out[e.from], i)
+		}
+	}
+	reversed := make([]bool, len(g.edges))
+	stateOf := make([]int, n) // 0: sin visitar, 1: en la pila, 2: terminado
+	order := make([]int, 0, n)
+	var dfs func(v int)
+	dfs = func(v int) {
+		stateOf[v] = 1
+		for _, i := range out[v] {
+			w := g.edges[i].to
+			switch stateOf[w] {
+			case 0:
+				dfs(w)
+			case 1:
+				reversed[i] = true
+			}
+		}
+		stateOf[v] = 2
+		order = append(order, v)
+	}
+	if n > 0 {
+		dfs(g.start)
+	}
+	for v := 0; v < n; v++ {
+		if stateOf[v] == 0 {
+			dfs(v)
+		}
+	}
+
+	// 2. Capas por camino más largo, en orden topológico (inverso del orden de finalización)
+	dagEnds := func(i int) (int, int) {
+		e := g.edges[i]
+		if reversed[i] {
+			return e.to, e.from
+		}
+		return e.from, e.to
+	}
+	dagOut := make([][]int, n)
+	for i, e := range g.edges {
+		if e.from != e.to {
+			u, _ := dagEnds(i)
+			dagOut[u] = append(dagOut[u], i)
+		}
+	}
+	layer := make([]int, n)
+	for k := len(order) - 1; k >= 0; k-- {
+		u := order[k]
+		for _, i := range dagOut[u] {
+			_, v := dagEnds(i)
+			if layer[u]+1 > layer[v] {
+				layer[v] = layer[u] + 1
+			}
+		}
+	}
+
+	// 3. Nodos ficticios: cadena de nodos virtuales para cada arista
+	vlayer := append([]int{}, layer...)
+	up := make([][]int, n)   // Vecinos en la capa anterior
+	down := make([][]int, n) // Vecinos en la capa siguiente
+	chains := make([][]int, len(g.edges))
+	for i, e := range g.edges {
+		if e.from == e.to {
+			continue
+		}
+		u, v := dagEnds(i)
+		chain := []int{u}
+		for l := layer[u] + 1; l < layer[v]; l++ {
+			vlayer = append(vlayer, l)
+			up = append(up, nil)
+			down = append(down, nil)
+			chain = append(chain, len(vlayer)-1)
+		}
+		chain = append(chain, v)
+		for k := 0; k+1 < len(chain); k++ {
+			down[chain[k]] = append(down[chain[k]], chain[k+1])
+			up[chain[k+1]] = append(up[chain[k+1]], chain[k])
+		}
+		chains[i] = chain
+	}
+
+	maxLayer := 0
+	for _, l := range vlayer {
+		if l > maxLayer {
+			maxLayer = l
+		}
+	}
+	layers := make([][]int, maxLayer+1)
+	for v, l := range vlayer {
+		layers[l] = append(layers[l], v)
+	}
+
+	// 4. Reducción de cruces con baricentros, conservando el mejor orden encontrado
+	pos := make([]float64, len(vlayer))
+	updatePos := func() {
+		for _, nodes := range layers {
+			for i, v := range nodes {
+				pos[v] = float64(i)
+			}
+		}
+	}
+	updatePos()
+	best := copyLayers(layers)
+	bestCrossings := countCrossings(layers, down, pos)
+	for iter := 0; iter < 24 && bestCrossings > 0; iter++ {
+		if iter%2 == 0 {
+			for l := 1; l <= maxLayer; l++ {
+				sortByBarycenter(layers[l], up, pos)
+				updatePos()
+			}
+		} else {
+			for l := maxLayer - 1; l >= 0; l-- {
+				sortByBarycenter(layers[l], down, pos)
+				updatePos()
+			}
+		}
+		if c := countCrossings(layers, down, pos); c < bestCrossings {
+			bestCrossings = c
+			best = copyLayers(layers)
+		}
+	}
+	layers = best
+
+	// 5. Coordenadas: columnas por capa, nodos centrados verticalmente
+	widest := 0
+	for _, nodes := range layers {
+		if len(nodes) > widest {
+			widest = len(nodes)
+		}
+	}
+	coords := make([]point, len(vlayer))
+	for l, nodes := range layers {
+		offset := float64(widest-len(nodes)) * rowGap / 2
+		for i, v := range nodes {
+			coords[v] = point{
+				x: margin + startSpace + nodeRadius + float64(l)*layerGap,
+				y: margin + loopSpace + nodeRadius + offset + float64(i)*rowGap,
+			}
+		}
+	}
+
+	d := &drawing{
+		pos:    coords[:n],
+		paths:  make([][]point, len(g.edges)),
+		width:  2*margin + startSpace + 2*nodeRadius + float64(maxLayer)*layerGap,
+		height: 2*margin + loopSpace + 2*nodeRadius + float64(widest-1)*rowGap,
+	}
+	for i, chain := range chains {
+		if chain == nil {
+			continue
+		}
+		path := make([]point, len(chain))
+		for k, v := range chain {
+			path[k] = coords[v]
+		}
+		if reversed[i] {
+			for a, b := 0, len(path)-1; a < b; a, b = a+1, b-1 {
+				path[a], path[b] = path[b], path[a]
+			}
+		}
+		d.paths[i] = path
+	}
+	return d
+}
+
+// sortByBarycenter ordena una capa según la posición promedio de los vecinos de cada nodo;
+// los nodos sin vecinos conservan su posición actual.
+func sortByBarycenter(nodes []int, neighbors [][]int, pos []float64) {
+	key := make(map[int]float64, len(nodes))
+	for _, v := range nodes {
+		if len(neighbors[v]) == 0 {
+			key[v] = pos[v]
+			continue
+		}
+		sum := 0.0
+		for _, w := range neighbors[v] {
+			sum += pos[w]
+		}
+		key[v] = sum / float64(len(neighbors[v]))
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return key[nodes[i]] < key[nodes[j]] })
+}
+
+// countCrossings cuenta los cruces entre aristas de capas consecutivas.
+func countCrossings(layers [][]int, down [][]int, pos []float64) int {
+	crossings := 0
+	for _, nodes := range layers {
+		var segs [][2]float64
+		for _, v := range nodes {
+			for _, w := range down[v] {
+				segs = append(segs, [2]float64{pos[v], pos[w]})
+			}
+		}
+		for i := range segs {
+			for j := i + 1; j < len(segs); j++ {
+				if (segs[i][0]-segs[j][0])*(segs[i][1]-segs[j][1]) < 0 {
+					crossings++
+				}
+			}
+		}
+	}
+	return crossings
+}
+
+// copyLayers copia el orden de las capas.
+func copyLayers(layers [][]int) [][]int {
+	cp := make([][]int, len(layers))
+	for i, nodes := range layers {
+		cp[i] = append([]int{}, nodes...)
+	}
+	return cp
+}
+
+// dfaStateNames asigna letras (A, B, ...) a los estados del DFA, y q0, q1, ... cuando se acaban.
+func dfaStateNames(dfa *nfa.DFA) map[string]string {
+	letters := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	names := map[string]string{}
+	for i, state := range dfa.States {
+		if i < len(letters) {
+			names[state] = string(letters[i])
+		} else {
+			// Si hay más estados que letras, usar q0, q1, etc.
+			names[state] = fmt.Sprintf("q%d", i-len(letters))
+		}
+	}
+	// Si el estado inicial no está en dfa.States se nombra q0
+	if _, ok := names[dfa.Start]; !ok {
+		names[dfa.Start] = "q0"
+	}
+	return names
+}
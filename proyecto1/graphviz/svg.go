@@ -0,0 +1,159 @@
+// Package graphviz proporciona funciones para generar archivos DOT de Graphviz,
+// incluyendo un renderizador SVG nativo para cuando el binario dot no está disponible.
+package graphviz
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"os/exec"
+	"proyecto1/nfa"
+	"proyecto1/thompson"
+	"strings"
+)
+
+// HasDot indica si el comando 'dot' de Graphviz está en el PATH.
+func HasDot() bool {
+	_, err := exec.LookPath("dot")
+	return err == nil
+}
+
+// WriteSVG escribe un dibujo SVG del NFA en la ruta especificada, sin usar Graphviz.
+func WriteSVG(n *thompson.NFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return renderSVG(w, nfaGraph(n)) })
+}
+
+// WriteSVGDFA escribe un dibujo SVG del DFA en la ruta especificada, sin usar Graphviz.
+// Los estados se nombran con letras como en WriteDOTDFA.
+func WriteSVGDFA(dfa *nfa.DFA, path string) error {
+	return writeFile(path, func(w io.Writer) error { return renderSVG(w, dfaGraph(dfa)) })
+}
+
+// RenderSVG escribe el dibujo SVG del NFA en w (por ejemplo, para incrustarlo en HTML).
+func RenderSVG(w io.Writer, n *thompson.NFA) error {
+	return renderSVG(w, nfaGraph(n))
+}
+
+// RenderSVGDFA escribe el dibujo SVG del DFA en w.
+func RenderSVGDFA(w io.Writer, dfa *nfa.DFA) error {
+	return renderSVG(w, dfaGraph(dfa))
+}
+
+// renderSVG dibuja el grafo con layoutGraph: círculos para los estados (doble círculo si son de
+// aceptación), una flecha hacia el estado inicial, lazos curvos sobre el estado y aristas suavizadas
+// que pasan por los nodos ficticios. Dos aristas opuestas entre los mismos estados se curvan para no superponerse.
+func renderSVG(w io.Writer, g *graph) error {
+	d := layoutGraph(g)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\" font-family=\"sans-serif\" font-size=\"14\">\n",
+		d.width, d.height, d.width, d.height)
+	b.WriteString("  <defs>\n")
+	b.WriteString("    <marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"8\" markerHeight=\"8\" orient=\"auto\">\n")
+	b.WriteString("      <path d=\"M0,0 L10,5 L0,10 z\" fill=\"black\"/>\n")
+	b.WriteString("    </marker>\n")
+	b.WriteString("  </defs>\n")
+	b.WriteString("  <rect width=\"100%\" height=\"100%\" fill=\"white\"/>\n")
+
+	// Flecha inicial
+	if len(g.nodes) > 0 {
+		s := d.pos[g.start]
+		fmt.Fprintf(&b, "  <line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"black\" marker-end=\"url(#arrow)\"/>\n",
+			s.x-nodeRadius-startSpace+5, s.y, s.x-nodeRadius, s.y)
+	}
+
+	// Aristas
+	pairs := map[[2]int]bool{}
+	for _, e := range g.edges {
+		pairs[[2]int{e.from, e.to}] = true
+	}
+	for i, e := range g.edges {
+		var path string
+		var label point
+		if e.from == e.to {
+			path, label = loopPath(d.pos[e.from])
+		} else {
+			bend := 0.0
+			if pairs[[2]int{e.to, e.from}] {
+				bend = 22
+			}
+			path, label = edgePath(d.paths[i], bend)
+		}
+		fmt.Fprintf(&b, "  <path d=\"%s\" fill=\"none\" stroke=\"black\" marker-end=\"url(#arrow)\"/>\n", path)
+		fmt.Fprintf(&b, "  <text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" dominant-baseline=\"middle\" stroke=\"white\" stroke-width=\"4\" paint-order=\"stroke\">%s</text>\n",
+			label.x, label.y, html.EscapeString(e.label))
+	}
+
+	// Estados
+	for i, node := range g.nodes {
+		p := d.pos[i]
+		fmt.Fprintf(&b, "  <g>\n    <title>%s</title>\n", html.EscapeString(node.title))
+		fmt.Fprintf(&b, "    <circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"white\" stroke=\"black\"/>\n", p.x, p.y, nodeRadius)
+		if node.accepting {
+			fmt.Fprintf(&b, "    <circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"none\" stroke=\"black\"/>\n", p.x, p.y, nodeRadius-4)
+		}
+		fmt.Fprintf(&b, "    <text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\" dominant-baseline=\"central\">%s</text>\n  </g>\n",
+			p.x, p.y, html.EscapeString(node.label))
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// loopPath retorna un lazo curvo sobre el estado en c y la posición de su etiqueta.
+func loopPath(c point) (string, point) {
+	from := point{c.x - nodeRadius*math.Sin(math.Pi/6), c.y - nodeRadius*math.Cos(math.Pi/6)}
+	to := point{c.x + nodeRadius*math.Sin(math.Pi/6), c.y - nodeRadius*math.Cos(math.Pi/6)}
+	top := c.y - nodeRadius - 38
+	path := fmt.Sprintf("M%.1f,%.1f C%.1f,%.1f %.1f,%.1f %.1f,%.1f",
+		from.x, from.y, c.x-28, top, c.x+28, top, to.x, to.y)
+	return path, point{c.x, top + 2}
+}
+
+// edgePath retorna el trazo de una arista que recorre los puntos dados (de centro a centro) y la
+// posición de su etiqueta. Los extremos se recortan al borde de los círculos. Una arista directa se
+// curva hacia su izquierda en bend píxeles; las que pasan por nodos ficticios se suavizan con
+// curvas de Bézier (Catmull-Rom).
+func edgePath(pts []point, bend float64) (string, point) {
+	pts = append([]point{}, pts...)
+	last := len(pts) - 1
+	pts[0] = towards(pts[0], pts[1], nodeRadius)
+	pts[last] = towards(pts[last], pts[last-1], nodeRadius)
+
+	if last == 1 {
+		a, b := pts[0], pts[1]
+		mid := point{(a.x + b.x) / 2, (a.y + b.y) / 2}
+		dx, dy := b.x-a.x, b.y-a.y
+		length := math.Hypot(dx, dy)
+		if length == 0 || bend == 0 {
+			return fmt.Sprintf("M%.1f,%.1f L%.1f,%.1f", a.x, a.y, b.x, b.y), point{mid.x, mid.y - 10}
+		}
+		nx, ny := dy/length, -dx/length // Normal hacia la izquierda del sentido de la arista (y hacia abajo en SVG)
+		ctrl := point{mid.x + nx*bend*2, mid.y + ny*bend*2}
+		return fmt.Sprintf("M%.1f,%.1f Q%.1f,%.1f %.1f,%.1f", a.x, a.y, ctrl.x, ctrl.y, b.x, b.y),
+			point{mid.x + nx*(bend+10), mid.y + ny*(bend+10)}
+	}
+
+	var path strings.Builder
+	fmt.Fprintf(&path, "M%.1f,%.1f", pts[0].x, pts[0].y)
+	for i := 0; i < last; i++ {
+		prev, next := pts[max(i-1, 0)], pts[min(i+2, last)]
+		c1 := point{pts[i].x + (pts[i+1].x-prev.x)/6, pts[i].y + (pts[i+1].y-prev.y)/6}
+		c2 := point{pts[i+1].x - (next.x-pts[i].x)/6, pts[i+1].y - (next.y-pts[i].y)/6}
+		fmt.Fprintf(&path, " C%.1f,%.1f %.1f,%.1f %.1f,%.1f", c1.x, c1.y, c2.x, c2.y, pts[i+1].x, pts[i+1].y)
+	}
+	mid := pts[len(pts)/2]
+	return path.String(), point{mid.x, mid.y - 10}
+}
+
+// towards mueve el punto from una distancia dist en dirección a to.
+func towards(from, to point, dist float64) point {
+	dx, dy := to.x-from.x, to.y-from.y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return from
+	}
+	return point{from.x + dx/length*dist, from.y + dy/length*dist}
+}
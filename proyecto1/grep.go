@@ -0,0 +1,280 @@
+// /proyecto1/grep.go
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"unicode/utf8"
+
+	"proyecto1/nfa"
+)
+
+// invalidSymbol es el símbolo con el que se alimenta al autómata un byte que no es UTF-8 válido:
+// no pertenece a ningún alfabeto, así que nunca tiene transición.
+const invalidSymbol rune = -1
+
+// stdinName es el nombre con el que grep muestra la entrada estándar.
+const stdinName = "(entrada estándar)"
+
+// grepMatcher decide si una línea coincide consumiéndola de a un símbolo con un Runner, en tiempo
+// lineal. En modo línea completa simula el DFA minimizado; en modo subcadena, el DFA (minimizado) de
+// Σ*·L, que acepta en cuanto termina una subcadena del lenguaje. Con un DFA perezoso (nfa.LazyDFA),
+// los estados se construyen a partir del NFA a medida que las líneas los visitan.
+type grepMatcher struct {
+	runner    nfa.Runner
+	alphabet  map[rune]bool
+	substring bool
+	matched   bool // Veredicto de la línea en curso (en modo subcadena, ya definitivo si es true)
+}
+
+// newGrepMatcher prepara el DFA de búsqueda para la regex compilada. Si lazyMem > 0, usa un DFA perezoso
+// con una caché de lazyMem bytes, y c solo necesita el NFA.
+func newGrepMatcher(c *compiled, wholeLine bool, lazyMem int) (*grepMatcher, error) {
+	m := &grepMatcher{alphabet: map[rune]bool{}, substring: !wholeLine}
+	for _, sym := range c.Alphabet {
+		m.alphabet[sym] = true
+	}
+	if lazyMem > 0 {
+		n := c.NFA
+		if m.substring {
+			n = nfa.Unanchored(n, c.Alphabet)
+		}
+		l, err := nfa.NewLazyDFA(n, lazyMem)
+		if err != nil {
+			return nil, err
+		}
+		m.runner = l
+		return m, nil
+	}
+	dfa := c.MinDFA
+	if m.substring {
+		dfa = nfa.MinimizeDFA(nfa.NFAtoDFA(nfa.Unanchored(c.MinDFA, c.Alphabet), c.Alphabet))
+	}
+	m.runner = nfa.NewDFARunner(dfa)
+	return m, nil
+}
+
+// begin empieza una línea nueva. En modo subcadena, si el lenguaje contiene ε toda línea coincide.
+func (m *grepMatcher) begin() {
+	m.runner.Reset()
+	m.matched = m.substring && m.runner.Accepting()
+}
+
+// step consume un símbolo de la línea.
+func (m *grepMatcher) step(sym rune) {
+	switch {
+	case m.substring && m.matched, !m.substring && m.runner.Dead():
+		return // Veredicto decidido: el resto de la línea no lo cambia
+	case m.substring && !m.alphabet[sym]:
+		m.runner.Reset() // Ninguna subcadena del lenguaje contiene sym: la búsqueda vuelve a empezar
+	default:
+		m.runner.Step(sym)
+	}
+	if m.substring {
+		m.matched = m.runner.Accepting()
+	}
+}
+
+// end termina la línea y retorna si coincide.
+func (m *grepMatcher) end() bool {
+	if !m.substring {
+		m.matched = m.runner.Accepting()
+	}
+	return m.matched
+}
+
+// grepStream lee r de a un rune y llama a visit con cada línea (sin el salto de línea) y su veredicto.
+// Las líneas terminan en \n o \r\n; la última puede no tener salto. Los bytes que no son UTF-8 válido
+// se conservan en la línea tal cual.
+func grepStream(r io.Reader, m *grepMatcher, visit func(lineNo int, line []byte, matched bool) error) error {
+	br := bufio.NewReader(r)
+	var line []byte
+	lineNo := 0
+	open := false // Hay una línea empezada
+	cr := false   // \r pendiente: es parte del salto si le sigue \n
+	for {
+		sym, size, err := br.ReadRune()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		eof := err == io.EOF
+		if cr && (eof || sym != '\n') {
+			line = append(line, '\r')
+			m.step('\r')
+		}
+		cr = false
+		if eof {
+			break
+		}
+		if !open {
+			m.begin()
+			line = line[:0]
+			open = true
+		}
+
+		switch {
+		case sym == '\n':
+			lineNo++
+			open = false
+			if err := visit(lineNo, line, m.end()); err != nil {
+				return err
+			}
+		case sym == '\r':
+			cr = true
+		case sym == utf8.RuneError && size == 1:
+			br.UnreadRune()
+			b, _ := br.ReadByte()
+			line = append(line, b)
+			m.step(invalidSymbol)
+		default:
+			line = utf8.AppendRune(line, sym)
+			m.step(sym)
+		}
+	}
+	if open {
+		lineNo++
+		return visit(lineNo, line, m.end())
+	}
+	return nil
+}
+
+// grepFile es el resultado de grep en un archivo.
+type grepFile struct {
+	File  string     `json:"file"`
+	Count int        `json:"count"`
+	Lines []grepLine `json:"lines,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+// grepLine es una línea seleccionada.
+type grepLine struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// openInput abre un archivo de entrada; "-" es la entrada estándar.
+func openInput(path string) (io.ReadCloser, string, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), stdinName, nil
+	}
+	f, err := os.Open(path)
+	return f, path, err
+}
+
+// cmdGrep filtra las líneas de los archivos (o de la entrada estándar) que contienen una subcadena del
+// lenguaje o, con -x, que pertenecen a él. Como grep, sale con exitFalse si no se seleccionó ninguna línea.
+func cmdGrep(fs *flag.FlagSet) cliRun {
+	wholeLine := fs.Bool("x", false, "la línea completa debe pertenecer al lenguaje (por defecto, basta una subcadena)")
+	invert := fs.Bool("v", false, "seleccionar las líneas que no coinciden")
+	count := fs.Bool("c", false, "mostrar solo la cantidad de líneas seleccionadas de cada archivo")
+	number := fs.Bool("n", false, "anteponer el número de línea")
+	lazy := fs.Bool("lazy", false, "construir el DFA bajo demanda (para patrones con un DFA exponencial)")
+	cacheSize := fs.Int("cache", nfa.DefaultLazyMemory, "con -lazy, límite de memoria de la caché de estados en bytes")
+	return func(cf *cliFlags, args []string) (int, error) {
+		if *cacheSize <= 0 {
+			return exitUsage, errUsage("-cache debe ser positivo")
+		}
+		input, lazyMem := cf.input, 0
+		if *lazy {
+			input, lazyMem = cf.inputNFA, *cacheSize
+		}
+		c, paths, err := input(args)
+		if err != nil {
+			return exitError, err
+		}
+		if len(paths) == 0 {
+			paths = []string{"-"}
+		}
+		m, err := newGrepMatcher(c, *wholeLine, lazyMem)
+		if err != nil {
+			return exitUsage, errUsage("-cache: %v", err)
+		}
+		w := bufio.NewWriter(cf.out)
+
+		// writeLine escribe una línea de salida con el prefijo del archivo (si hay varios) y del número de línea
+		writeLine := func(name string, lineNo int, text []byte) error {
+			if len(paths) > 1 {
+				w.WriteString(name + ":")
+			}
+			if lineNo > 0 {
+				w.WriteString(strconv.Itoa(lineNo) + ":")
+			}
+			w.Write(text)
+			return w.WriteByte('\n')
+		}
+
+		files := []grepFile{}
+		selected, failed := false, false
+		for _, path := range paths {
+			r, name, err := openInput(path)
+			if err != nil {
+				failed = true
+				files = append(files, grepFile{File: path, Error: err.Error()})
+				if !cf.json {
+					fmt.Fprintf(os.Stderr, "proyecto1 grep: %v\n", err)
+				}
+				continue
+			}
+			res := grepFile{File: name}
+			var writeErr error
+			err = grepStream(r, m, func(lineNo int, line []byte, matched bool) error {
+				if matched == *invert {
+					return nil
+				}
+				res.Count++
+				switch {
+				case *count:
+				case cf.json:
+					res.Lines = append(res.Lines, grepLine{Line: lineNo, Text: string(line)})
+				default:
+					if !*number {
+						lineNo = 0
+					}
+					writeErr = writeLine(name, lineNo, line)
+				}
+				return writeErr
+			})
+			r.Close()
+			if writeErr != nil {
+				return exitError, writeErr
+			}
+			if err != nil {
+				failed = true
+				res.Error = err.Error()
+				if !cf.json {
+					fmt.Fprintf(os.Stderr, "proyecto1 grep: %s: %v\n", name, err)
+				}
+			}
+			if *count && !cf.json {
+				if len(paths) > 1 {
+					w.WriteString(name + ":")
+				}
+				fmt.Fprintln(w, res.Count)
+			}
+			selected = selected || res.Count > 0
+			files = append(files, res)
+		}
+		if err := w.Flush(); err != nil {
+			return exitError, err
+		}
+		if cf.json {
+			out := map[string]any{"files": files}
+			if l, ok := m.runner.(*nfa.LazyDFA); ok {
+				out["lazy"] = l.Stats()
+			}
+			cf.emit(out, nil)
+		}
+
+		switch {
+		case failed:
+			return exitError, nil
+		case selected:
+			return exitOK, nil
+		}
+		return exitFalse, nil
+	}
+}
@@ -0,0 +1,74 @@
+// /proyecto1/grep_test.go
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"proyecto1/nfa"
+)
+
+// grepLines ejecuta grepStream sobre input y retorna las líneas con su veredicto.
+func grepLines(t *testing.T, m *grepMatcher, input string) ([]string, []bool) {
+	t.Helper()
+	lines, verdicts := []string{}, []bool{}
+	err := grepStream(strings.NewReader(input), m, func(lineNo int, line []byte, matched bool) error {
+		if lineNo != len(lines)+1 {
+			t.Errorf("línea %d numerada %d", len(lines)+1, lineNo)
+		}
+		lines = append(lines, string(line))
+		verdicts = append(verdicts, matched)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lines, verdicts
+}
+
+func TestGrepStream(t *testing.T) {
+	tests := []struct {
+		name, regex, input string
+		lines              []string
+		whole, substring   []bool // Veredictos con -x y en modo subcadena
+	}{
+		{"LF", "ab", "ab\nxab\nb\n", []string{"ab", "xab", "b"}, []bool{true, false, false}, []bool{true, true, false}},
+		{"CRLF", "ab", "ab\r\nxab\r\n", []string{"ab", "xab"}, []bool{true, false}, []bool{true, true}},
+		{"\\r suelto", "ab", "a\rb\nab\rab\n", []string{"a\rb", "ab\rab"}, []bool{false, false}, []bool{false, true}},
+		{"\\r al final sin salto", "ab", "ab\r", []string{"ab\r"}, []bool{false}, []bool{true}},
+		{"líneas vacías", "ab", "ab\r\n\r\n\n", []string{"ab", "", ""}, []bool{true, false, false}, []bool{true, false, false}},
+		{"UTF-8 inválido", "ab", "a\xffb\nab\xff\n", []string{"a\xffb", "ab\xff"}, []bool{false, false}, []bool{false, true}},
+		{"UTF-8 válido", "ñb", "ñb\nxñb\n", []string{"ñb", "xñb"}, []bool{true, false}, []bool{true, true}},
+		{"última línea sin salto", "ab", "b\nab", []string{"b", "ab"}, []bool{false, true}, []bool{false, true}},
+		{"entrada vacía", "ab", "", []string{}, []bool{}, []bool{}},
+		{"ε en el lenguaje", "a*", "a\nb\n\n", []string{"a", "b", ""}, []bool{true, false, true}, []bool{true, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := compilePipeline(tt.regex, nil, nfa.Hopcroft)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, mode := range []struct {
+				wholeLine bool
+				want      []bool
+			}{{true, tt.whole}, {false, tt.substring}} {
+				// DFA minimizado, DFA perezoso y DFA perezoso con una caché que se vacía
+				for _, lazyMem := range []int{0, nfa.DefaultLazyMemory, 600} {
+					m, err := newGrepMatcher(c, mode.wholeLine, lazyMem)
+					if err != nil {
+						t.Fatal(err)
+					}
+					lines, verdicts := grepLines(t, m, tt.input)
+					if !reflect.DeepEqual(lines, tt.lines) {
+						t.Errorf("-x=%v, caché %d: líneas %q, se esperaban %q", mode.wholeLine, lazyMem, lines, tt.lines)
+					}
+					if !reflect.DeepEqual(verdicts, mode.want) {
+						t.Errorf("-x=%v, caché %d: veredictos %v, se esperaban %v", mode.wholeLine, lazyMem, verdicts, mode.want)
+					}
+				}
+			}
+		})
+	}
+}
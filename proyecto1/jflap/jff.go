@@ -0,0 +1,248 @@
+// Package jflap lee y escribe autómatas finitos en el formato XML de JFLAP (.jff).
+// Los autómatas se convierten desde y hacia los tipos del proyecto (*thompson.NFA y *nfa.DFA)
+// usando la representación intermedia nfa.JSONAutomaton, y se conservan las coordenadas de los estados.
+package jflap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"proyecto1/nfa"
+	"proyecto1/thompson"
+	"sort"
+	"strconv"
+)
+
+// Point son las coordenadas de un estado en el lienzo de JFLAP.
+type Point struct {
+	X, Y float64
+}
+
+// Layout asocia el nombre de cada estado con sus coordenadas.
+type Layout map[string]Point
+
+// jffStructure es la raíz de un archivo .jff. JFLAP 7 agrupa estados y transiciones dentro de
+// <automaton>; las versiones anteriores los ponen directamente bajo <structure>.
+type jffStructure struct {
+	XMLName     xml.Name        `xml:"structure"`
+	Type        string          `xml:"type"`
+	Automaton   *jffAutomaton   `xml:"automaton,omitempty"`
+	States      []jffState      `xml:"state,omitempty"`
+	Transitions []jffTransition `xml:"transition,omitempty"`
+}
+
+// jffAutomaton contiene los estados y transiciones (formato de JFLAP 7).
+type jffAutomaton struct {
+	States      []jffState      `xml:"state"`
+	Transitions []jffTransition `xml:"transition"`
+}
+
+// jffState es un estado de JFLAP; <initial/> y <final/> son marcas vacías.
+type jffState struct {
+	ID      string    `xml:"id,attr"`
+	Name    string    `xml:"name,attr"`
+	X       *float64  `xml:"x"`
+	Y       *float64  `xml:"y"`
+	Initial *struct{} `xml:"initial"`
+	Final   *struct{} `xml:"final"`
+}
+
+// jffTransition es una transición de JFLAP; <read/> vacío es λ (ε).
+type jffTransition struct {
+	From string `xml:"from"`
+	To   string `xml:"to"`
+	Read string `xml:"read"`
+}
+
+// ReadFile lee un archivo .jff (ver Read).
+func ReadFile(path string) (nfa.Automaton, Layout, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read lee un autómata finito en formato JFLAP. Retorna un *nfa.DFA si el autómata es determinista
+// (sin λ, un solo símbolo por transición y a lo sumo un destino por estado y símbolo) y un
+// *thompson.NFA en otro caso, junto con las coordenadas de los estados que las tengan.
+// Las transiciones que leen varios símbolos se expanden con estados intermedios.
+func Read(r io.Reader) (nfa.Automaton, Layout, error) {
+	var doc jffStructure
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("XML de JFLAP inválido: %v", err)
+	}
+	if doc.Type != "" && doc.Type != "fa" {
+		return nil, nil, fmt.Errorf("tipo de máquina JFLAP %q no soportado (solo autómatas finitos, \"fa\")", doc.Type)
+	}
+	states, transitions := doc.States, doc.Transitions
+	if doc.Automaton != nil {
+		states = append(states, doc.Automaton.States...)
+		transitions = append(transitions, doc.Automaton.Transitions...)
+	}
+	if len(states) == 0 {
+		return nil, nil, fmt.Errorf("el archivo no tiene estados")
+	}
+
+	// Nombres de los estados (el atributo name, o "q<id>" si falta o se repite)
+	names := make(map[string]string, len(states))
+	used := map[string]bool{}
+	j := &nfa.JSONAutomaton{Type: nfa.JSONTypeDFA}
+	layout := Layout{}
+	for _, s := range states {
+		if _, dup := names[s.ID]; dup {
+			return nil, nil, fmt.Errorf("id de estado repetido %q", s.ID)
+		}
+		name := s.Name
+		if name == "" || used[name] {
+			name = "q" + s.ID
+		}
+		if used[name] {
+			return nil, nil, fmt.Errorf("no se pudo asignar un nombre único al estado %q", s.ID)
+		}
+		used[name] = true
+		names[s.ID] = name
+		j.States = append(j.States, name)
+		if s.Initial != nil {
+			if j.Start != "" {
+				return nil, nil, fmt.Errorf("hay más de un estado inicial (%s y %s)", j.Start, name)
+			}
+			j.Start = name
+		}
+		if s.Final != nil {
+			j.Accepting = append(j.Accepting, name)
+		}
+		if s.X != nil && s.Y != nil {
+			layout[name] = Point{X: *s.X, Y: *s.Y}
+		}
+	}
+	if j.Start == "" {
+		return nil, nil, fmt.Errorf("el autómata no tiene estado inicial")
+	}
+
+	// Transiciones (las de varios símbolos se expanden con estados intermedios)
+	alphabet := map[rune]bool{}
+	targets := map[string]map[rune]bool{}
+	for i, t := range transitions {
+		from, okFrom := names[t.From]
+		to, okTo := names[t.To]
+		if !okFrom || !okTo {
+			return nil, nil, fmt.Errorf("la transición %d usa un estado inexistente (%s → %s)", i+1, t.From, t.To)
+		}
+		read := []rune(t.Read)
+		if len(read) == 0 {
+			j.Type = nfa.JSONTypeNFA
+			j.Transitions = append(j.Transitions, nfa.JSONTransition{From: from, Symbol: "ε", To: to})
+			continue
+		}
+		if len(read) > 1 {
+			j.Type = nfa.JSONTypeNFA
+		}
+		cur := from
+		for k, sym := range read {
+			alphabet[sym] = true
+			next := to
+			if k < len(read)-1 {
+				// Un estado del archivo puede llamarse igual que el intermedio: se agregan apóstrofos
+				next = fmt.Sprintf("%s_%d_%d", from, i, k)
+				for used[next] {
+					next += "'"
+				}
+				used[next] = true
+				j.States = append(j.States, next)
+			}
+			if targets[cur] == nil {
+				targets[cur] = map[rune]bool{}
+			}
+			if targets[cur][sym] {
+				j.Type = nfa.JSONTypeNFA // Dos destinos para el mismo estado y símbolo
+			}
+			targets[cur][sym] = true
+			j.Transitions = append(j.Transitions, nfa.JSONTransition{From: cur, Symbol: string(sym), To: next})
+			cur = next
+		}
+	}
+	syms := make([]rune, 0, len(alphabet))
+	for sym := range alphabet {
+		syms = append(syms, sym)
+	}
+	sort.Slice(syms, func(a, b int) bool { return syms[a] < syms[b] })
+	for _, sym := range syms {
+		j.Alphabet = append(j.Alphabet, string(sym))
+	}
+
+	if j.Type == nfa.JSONTypeDFA {
+		d, err := j.ToDFA()
+		return d, layout, err
+	}
+	n, err := j.ToNFA()
+	if err != nil {
+		return nil, nil, err
+	}
+	// En el NFA los estados se nombran q<ID>; ToNFA conserva el orden de j.States
+	nfaLayout := Layout{}
+	for i, name := range j.States {
+		if p, ok := layout[name]; ok {
+			nfaLayout[fmt.Sprintf("q%d", n.States[i].ID)] = p
+		}
+	}
+	return n, nfaLayout, nil
+}
+
+// WriteNFA escribe el NFA en formato JFLAP; los estados se nombran q<ID> como en los archivos DOT.
+func WriteNFA(w io.Writer, n *thompson.NFA, layout Layout) error {
+	return write(w, nfa.NFAToJSON(n), layout)
+}
+
+// WriteDFA escribe el DFA en formato JFLAP, conservando los nombres de los estados.
+func WriteDFA(w io.Writer, d *nfa.DFA, layout Layout) error {
+	return write(w, nfa.DFAToJSON(d), layout)
+}
+
+// write convierte la representación intermedia en un documento .jff. Los estados sin coordenadas
+// en layout se ubican en una cuadrícula para que JFLAP no los dibuje superpuestos.
+func write(w io.Writer, j *nfa.JSONAutomaton, layout Layout) error {
+	ids := make(map[string]string, len(j.States))
+	accepting := make(map[string]bool, len(j.Accepting))
+	for _, s := range j.Accepting {
+		accepting[s] = true
+	}
+
+	auto := &jffAutomaton{}
+	for i, name := range j.States {
+		id := strconv.Itoa(i)
+		ids[name] = id
+		p, ok := layout[name]
+		if !ok {
+			p = Point{X: float64(100 + 150*(i%6)), Y: float64(100 + 150*(i/6))}
+		}
+		st := jffState{ID: id, Name: name, X: &p.X, Y: &p.Y}
+		if name == j.Start {
+			st.Initial = &struct{}{}
+		}
+		if accepting[name] {
+			st.Final = &struct{}{}
+		}
+		auto.States = append(auto.States, st)
+	}
+	for _, t := range j.Transitions {
+		read := t.Symbol
+		if read == "ε" {
+			read = "" // λ en JFLAP
+		}
+		auto.Transitions = append(auto.Transitions, jffTransition{From: ids[t.From], To: ids[t.To], Read: read})
+	}
+
+	if _, err := io.WriteString(w, xml.Header+"<!--Created with proyecto1.-->\n"); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(jffStructure{Type: "fa", Automaton: auto}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
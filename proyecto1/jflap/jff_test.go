@@ -0,0 +1,84 @@
+package jflap
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"proyecto1/nfa"
+	"proyecto1/thompson"
+)
+
+// accepts evalúa la cadena en el autómata leído.
+func accepts(a nfa.Automaton, w string) bool {
+	if d, ok := a.(*nfa.DFA); ok {
+		return nfa.SimulateDFA(d, w)
+	}
+	return nfa.Simulate(a.AsNFA(), w)
+}
+
+// startPoint retorna las coordenadas del estado inicial.
+func startPoint(a nfa.Automaton, layout Layout) (Point, bool) {
+	name := ""
+	switch a := a.(type) {
+	case *nfa.DFA:
+		name = a.Start
+	case *thompson.NFA:
+		name = fmt.Sprintf("q%d", a.Start.ID)
+	}
+	p, ok := layout[name]
+	return p, ok
+}
+
+func TestReadWriteRoundTrip(t *testing.T) {
+	tests := []struct {
+		file   string
+		dfa    bool
+		start  Point
+		accept []string
+		reject []string
+	}{
+		{"multi.jff", false, Point{10, 20}, []string{"ab", "abc", "abcc"}, []string{"", "a", "ac", "abb"}},
+		{"dfa.jff", true, Point{100, 150}, []string{"", "aa", "abab", "abba"}, []string{"a", "ab", "bab", "aaa"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			a, layout, err := ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for round := 1; round <= 2; round++ {
+				if _, isDFA := a.(*nfa.DFA); isDFA != tt.dfa {
+					t.Fatalf("lectura %d: se obtuvo %T", round, a)
+				}
+				if p, ok := startPoint(a, layout); !ok || p != tt.start {
+					t.Errorf("lectura %d: el estado inicial está en %v (%v), se esperaba %v", round, p, ok, tt.start)
+				}
+				for _, w := range tt.accept {
+					if !accepts(a, w) {
+						t.Errorf("lectura %d: %q rechazada", round, w)
+					}
+				}
+				for _, w := range tt.reject {
+					if accepts(a, w) {
+						t.Errorf("lectura %d: %q aceptada", round, w)
+					}
+				}
+
+				var buf bytes.Buffer
+				if d, ok := a.(*nfa.DFA); ok {
+					err = WriteDFA(&buf, d, layout)
+				} else {
+					err = WriteNFA(&buf, a.AsNFA(), layout)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if a, layout, err = Read(&buf); err != nil {
+					t.Fatalf("relectura: %v", err)
+				}
+			}
+		})
+	}
+}
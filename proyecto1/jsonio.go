@@ -0,0 +1,97 @@
+// /proyecto1/jsonio.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"proyecto1/config"
+	"proyecto1/jflap"
+	"proyecto1/nfa"
+	"proyecto1/thompson"
+)
+
+// writeOutputFile crea el archivo path y escribe en él con encode (JSON o JFLAP), informando el resultado.
+func writeOutputFile(logOut *log.Logger, path string, encode func(w io.Writer) error) {
+	f, err := os.Create(path)
+	if err == nil {
+		err = encode(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		logOut.Printf("  Error al guardar %s: %v\n", path, err)
+		return
+	}
+	logOut.Printf("  Guardado: %s\n", path)
+}
+
+// writeNFAJSON guarda el NFA en formato JSON.
+func writeNFAJSON(logOut *log.Logger, n *thompson.NFA, path string) {
+	writeOutputFile(logOut, path, func(w io.Writer) error { return nfa.EncodeNFA(w, n) })
+}
+
+// writeDFAJSON guarda el DFA en formato JSON.
+func writeDFAJSON(logOut *log.Logger, d *nfa.DFA, path string) {
+	writeOutputFile(logOut, path, func(w io.Writer) error { return nfa.EncodeDFA(w, d) })
+}
+
+// writeNFAJFF guarda el NFA en formato JFLAP (.jff).
+func writeNFAJFF(logOut *log.Logger, n *thompson.NFA, layout jflap.Layout, path string) {
+	writeOutputFile(logOut, path, func(w io.Writer) error { return jflap.WriteNFA(w, n, layout) })
+}
+
+// writeDFAJFF guarda el DFA en formato JFLAP (.jff).
+func writeDFAJFF(logOut *log.Logger, d *nfa.DFA, layout jflap.Layout, path string) {
+	writeOutputFile(logOut, path, func(w io.Writer) error { return jflap.WriteDFA(w, d, layout) })
+}
+
+// loadAutomaton lee un autómata desde JSON o, si la extensión es .jff, desde JFLAP
+// (en ese caso retorna también las coordenadas de los estados).
+func loadAutomaton(path string) (nfa.Automaton, jflap.Layout, error) {
+	if strings.EqualFold(filepath.Ext(path), ".jff") {
+		return jflap.ReadFile(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	a, err := nfa.DecodeAutomaton(f)
+	return a, nil, err
+}
+
+// runLoad carga un autómata (NFA o DFA) desde un archivo JSON o JFLAP en lugar de construirlo desde
+// una regex, lo exporta (ver emitAutomaton) y evalúa las cadenas de wordsCSV.
+func runLoad(path, sigmaSpec, wordsCSV string, opts lineOptions) error {
+	a, layout, err := loadAutomaton(path)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	opts.out.layout = layout
+
+	sigma := nfa.Alphabet(a)
+	if sigmaSpec != "" {
+		declared, err := config.ParseAlphabet(sigmaSpec)
+		if err != nil {
+			return fmt.Errorf("alfabeto inválido: %v", err)
+		}
+		for _, c := range sigma {
+			if !config.ContainsRune(declared, c) {
+				return fmt.Errorf("el autómata usa el símbolo %q, que no pertenece a Σ = %s", c, config.FormatAlphabet(declared))
+			}
+		}
+		sigma = declared
+	}
+
+	logOut := log.New(os.Stdout, "", 0)
+	logOut.Printf("Autómata cargado: %s\n", path)
+	logOut.Printf("  Alfabeto: %s\n", config.FormatAlphabet(sigma))
+	name := "load_" + strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return emitAutomaton(logOut, name, a, sigma, wordsCSV, opts)
+}
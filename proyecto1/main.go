@@ -2,13 +2,14 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"runtime"
 	"strings"
 
 	"proyecto1/config"
@@ -19,13 +20,83 @@ import (
 )
 
 func main() {
+	// Subcomandos: proyecto1 build|match|minimize|equiv|render|enumerate|convert|verify|test|serve|repl|help ...
+	if len(os.Args) > 1 {
+		if cmd := findSubcommand(os.Args[1]); cmd != nil {
+			os.Exit(cmd.run(os.Args[2:]))
+		}
+	}
+
 	// Flags
 	inPath := flag.String("in", "input.txt", "ruta al archivo de entrada")
 	dotDir := flag.String("dotout", "dotout", "directorio de salida para archivos DOT")
 	pngDir := flag.String("pngout", "pngout", "directorio de salida para archivos PNG")
 	outPath := flag.String("out", "output.txt", "archivo de salida para logs")
+	minName := flag.String("min", "hopcroft", "algoritmo de minimización: hopcroft, moore, brzozowski o check (compara todos)")
+	complete := flag.Bool("complete", false, "mostrar DFAs totales, con estado trampa explícito")
+	trim := flag.Bool("trim", false, "eliminar estados inalcanzables y muertos de los DFAs")
+	opName := flag.String("op", "", "operación de cerradura sobre las regex dadas como argumentos ("+strings.Join(closureOpNames(), ", ")+")")
+	opWords := flag.String("words", "", "cadenas a evaluar con -op o -load (separadas por coma)")
+	opSigma := flag.String("sigma", "", "alfabeto para -op o -load (por defecto, los símbolos del autómata)")
+	opMap := flag.String("map", "", "archivo de mapeo 'a -> cadena' para -op hom, invhom y subst")
+	jsonOut := flag.Bool("json", false, "guardar también cada etapa (NFA, DFA, minDFA) en JSON junto a los DOT")
+	formatSpec := flag.String("format", "dot", "formatos de texto para los autómatas, separados por coma ("+strings.Join(textFormatNames(), ", ")+")")
+	htmlPath := flag.String("html", "", "generar además un reporte HTML autocontenido en esta ruta")
+	metricsPath := flag.String("metrics", "", "guardar las métricas de cada línea (tamaño de la regex y estados de cada autómata) en este CSV")
+	svgOut := flag.Bool("svg", false, "dibujar los autómatas como SVG con el renderizador nativo en lugar de PNG con Graphviz")
+	jffOut := flag.Bool("jff", false, "guardar también cada etapa (NFA, DFA, minDFA) en formato JFLAP (.jff) junto a los DOT")
+	loadPath := flag.String("load", "", "cargar un autómata desde JSON o JFLAP (.jff) en lugar de leer regex (usa -words y -sigma)")
+	verify := flag.Bool("verify", false, "señalar las cadenas en que NFA, DFA y DFA minimizado no coinciden y compararlos en todas las cadenas cortas")
+	verifyLen := flag.Int("verifylen", defaultVerifyLen, "longitud máxima de las cadenas comparadas con -verify")
+	syntaxName := flag.String("syntax", "proyecto", "sintaxis de las regex de entrada: proyecto, perl o posix (regexp de Go)")
+	jobs := flag.Int("j", runtime.NumCPU(), "cantidad de líneas que se procesan en paralelo (la salida conserva el orden)")
 	flag.Parse()
 
+	if *complete && *trim {
+		log.Fatal("las flags -complete y -trim son excluyentes")
+	}
+	if *jobs < 1 {
+		log.Fatal("la flag -j debe ser al menos 1")
+	}
+	minAlg, err := nfa.ParseMinimizeAlgorithm(*minName)
+	if err != nil {
+		log.Fatalf("flag -min inválido: %v", err)
+	}
+
+	syntax, err := regex.ParseSyntax(*syntaxName)
+	if err != nil {
+		log.Fatalf("flag -syntax inválido: %v", err)
+	}
+
+	formats, err := parseFormats(*formatSpec)
+	if err != nil {
+		log.Fatalf("flag -format inválido: %v", err)
+	}
+
+	// Flags que comparten el procesamiento de cada línea, -op y -load
+	out := outputOptions{dotDir: *dotDir, pngDir: *pngDir, formats: formats, json: *jsonOut, jff: *jffOut, svg: *svgOut}
+	if !out.svg && !graphviz.HasDot() {
+		fmt.Fprintln(os.Stderr, "Graphviz (dot) no está instalado: se generarán imágenes SVG con el renderizador nativo")
+		out.svg = true
+	}
+	opts := lineOptions{minAlg: minAlg, complete: *complete, trim: *trim, verify: *verify, verifyLen: *verifyLen, syntax: syntax, out: out}
+
+	// Modo operación: proyecto1 -op union 'regex1' 'regex2' ...
+	if *opName != "" {
+		if err := runClosureOp(*opName, flag.Args(), *opSigma, *opMap, *opWords, opts); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Modo carga: proyecto1 -load automata.json -words ab,ba (o automata.jff)
+	if *loadPath != "" {
+		if err := runLoad(*loadPath, *opSigma, *opWords, opts); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Salida a consola + archivo
 	outFile, err := os.Create(*outPath)
 	if err != nil {
@@ -47,147 +118,231 @@ func main() {
 	}
 	defer f.Close()
 
-	sc := bufio.NewScanner(f)
-	// Ampliar buffer por si hay líneas largas
-	buf := make([]byte, 0, 1024*1024)
-	sc.Buffer(buf, 1024*1024)
-
-	lineNo := 0
+	var report []*reportLine // Secciones del reporte HTML (solo con -html)
+	var metrics []metricsRow // Filas del CSV de métricas (solo con -metrics)
+	var sum suiteSummary     // Casos con veredicto esperado (+w / -w)
+	divergentLines := 0      // Líneas en que -verify encontró discrepancias
 
-	for sc.Scan() {
-		lineNo++
-		raw := strings.TrimSpace(sc.Text())
-		if raw == "" || strings.HasPrefix(raw, "#") {
-			continue
+	// Procesar las líneas con -j trabajadores; la salida conserva el orden de la entrada.
+	// El primer error fatal (o Ctrl-C) cancela las líneas pendientes.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = processLines(ctx, f, *jobs, func(ctx context.Context, in inputLine) *lineResult {
+		return processLine(ctx, in, opts)
+	}, func(res *lineResult) error {
+		if err := res.output.flush(os.Stdout, outFile); err != nil {
+			return fmt.Errorf("no se pudo escribir la salida: %v", err)
 		}
-
-		// regex ; w1,w2,w3
-		parts := strings.SplitN(raw, ";", 2)
-		if len(parts) != 2 {
-			logConsole.Printf("Línea %d: formato inválido. Se esperaba 'regex;w1,w2,...'. Se encontró: %q\n", lineNo, raw)
-			continue
+		if res.report != nil && *htmlPath != "" {
+			report = append(report, res.report)
 		}
-		r := strings.TrimSpace(parts[0])
-		wsCSV := strings.TrimSpace(parts[1])
-		if r == "" {
-			logConsole.Printf("Línea %d: regex vacía antes de ';'\n", lineNo)
-			continue
+		if res.report != nil && res.report.Metrics != nil && *metricsPath != "" {
+			metrics = append(metrics, metricsRow{LineNo: res.report.LineNo, Regex: res.report.Original, Metrics: *res.report.Metrics})
 		}
-		if wsCSV == "" {
-			logConsole.Printf("Línea %d: no hay cadenas después de ';'\n", lineNo)
-			continue
+		sum.merge(res.sum)
+		if res.diverged {
+			divergentLines++
 		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		// Lista de cadenas (separadas por coma)
-		var words []string
-		for _, tok := range strings.Split(wsCSV, ",") {
-			w := strings.TrimSpace(tok)
-			if w != "" {
-				words = append(words, w)
-			}
+	if *htmlPath != "" {
+		if err := writeHTMLReport(*htmlPath, *inPath, report); err != nil {
+			log.Fatalf("no se pudo generar el reporte HTML: %v", err)
+		}
+		logConsole.Printf("Reporte HTML guardado: %s\n", *htmlPath)
+	}
+	if *metricsPath != "" {
+		if err := writeMetricsCSV(*metricsPath, metrics); err != nil {
+			log.Fatalf("no se pudieron guardar las métricas: %v", err)
 		}
-		if len(words) == 0 {
-			logConsole.Printf("Línea %d: no hay cadenas válidas para evaluar\n", lineNo)
-			continue
+		logConsole.Printf("Métricas guardadas: %s (%d líneas)\n", *metricsPath, len(metrics))
+		if r, ok := largestBlowup(metrics); ok {
+			logConsole.Printf("Mayor explosión de estados: línea %d (%s), %d estados del DFA para %d del NFA (%.2f)\n",
+				r.LineNo, r.Regex, r.DFAStates, r.NFAStates, r.DFAPerNFA)
 		}
+	}
+
+	// Resumen de las cadenas con veredicto esperado (+w / -w)
+	if sum.Cases > 0 {
+		logBoth.Printf("Resumen: %d casos con veredicto esperado, %d correctos, %d fallidos\n", sum.Cases, sum.Passed, sum.Failed)
+	}
+	if *verify {
+		logBoth.Printf("Verificación: %d líneas con discrepancias entre NFA, DFA y DFA minimizado\n", divergentLines)
+	}
+	if sum.Failed > 0 || divergentLines > 0 {
+		f.Close()
+		outFile.Close()
+		os.Exit(1)
+	}
+}
 
-		// Expandir, formatear, postfix
-		expanded := config.ExpandRegexExtensions(r)
-		formatted := config.FormatRegex(expanded)
-		postfix := config.InfixToPostfix(formatted)
+// lineOptions son las flags que comparten el procesamiento de cada línea y los modos -op y -load.
+type lineOptions struct {
+	minAlg    nfa.MinimizeAlgorithm
+	complete  bool // DFAs totales, con estado trampa
+	trim      bool // DFAs sin estados inalcanzables ni muertos
+	verify    bool // Comparar NFA, DFA y DFA minimizado en cada cadena y en las de longitud ≤ verifyLen
+	verifyLen int
+	syntax    regex.Syntax // Sintaxis de las regex (de cada línea y de los operandos de -op)
+	out       outputOptions
+}
 
-		logBoth.Printf("Línea %d\n", lineNo)
-		logBoth.Printf("  Regex original: %s\n", r)
-		logBoth.Printf("  Expandida: %s\n", expanded)
-		logBoth.Printf("  Formateada: %s\n", formatted)
-		logBoth.Printf("  Postfija: %s\n", postfix)
+// processLine procesa una línea de la entrada: construye el AST, el NFA, el DFA y el DFA minimizado,
+// los exporta y evalúa las cadenas. La salida se acumula en el resultado para escribirla en orden.
+func processLine(ctx context.Context, in inputLine, opts lineOptions) *lineResult {
+	res := &lineResult{}
+	logBoth, logConsole := res.output.loggers()
+	lineNo, r, words, sigma := in.No, in.Regex, in.Words, in.Sigma
 
-		// AST
-		ast, err := regex.BuildAST(postfix)
-		if err != nil {
-			logConsole.Printf("  Error de AST: %v\n\n", err)
-			continue
-		}
+	logBoth.Printf("Línea %d\n", lineNo)
+	logBoth.Printf("  Regex original: %s\n", r)
+
+	line := &reportLine{LineNo: lineNo, Original: r}
+	res.report = line
+	// Las cadenas marcadas que no se llegan a evaluar por un error de la línea cuentan como fallas
+	defer func() { res.sum.add(words, line.Words) }()
 
-		// NFA (Thompson)
-		nfaObj, err := thompson.Build(ast)
+	// Regex en sintaxis Perl o POSIX: se convierte a la del proyecto
+	if opts.syntax != regex.NativeSyntax {
+		imported, err := regex.Import(r, opts.syntax)
 		if err != nil {
-			logConsole.Printf("  Error de Thompson: %v\n\n", err)
-			continue
+			logConsole.Printf("  Error de importación: %v\n\n", err)
+			line.Err = fmt.Sprintf("error de importación: %v", err)
+			return res
 		}
+		r = imported.String()
+		logBoth.Printf("  Importada: %s\n", r)
+	}
 
-		// DOT/PNG NFA
-		dotPath := filepath.Join(*dotDir, fmt.Sprintf("nfa_%03d.dot", lineNo))
-		pngPath := filepath.Join(*pngDir, fmt.Sprintf("nfa_%03d.png", lineNo))
-		if err := graphviz.WriteDOT(nfaObj, dotPath); err != nil {
-			logConsole.Printf("  Error DOT: %v\n\n", err)
-		} else {
-			logConsole.Printf("  DOT guardado: %s\n", dotPath)
-			if err := graphviz.GeneratePNGFromDot(dotPath, pngPath); err != nil {
-				logConsole.Printf("  Error PNG (¿está instalado Graphviz?): %v\n\n", err)
-			} else {
-				logConsole.Printf("  PNG guardado: %s\n", pngPath)
-			}
-		}
+	// Expandir, formatear, postfix
+	expanded := config.ExpandRegexExtensions(r)
+	formatted := config.FormatRegex(expanded)
+	postfix := config.InfixToPostfix(formatted)
+	line.Expanded, line.Formatted, line.Postfix = expanded, formatted, postfix
 
-		// Alfabeto para NFA→DFA
-		alphabet := []rune{}
-		for _, c := range formatted {
-			if config.IsAlphanumeric(c) && c != 'ε' && !config.ContainsRune(alphabet, c) {
-				alphabet = append(alphabet, c)
-			}
-		}
+	logBoth.Printf("  Expandida: %s\n", expanded)
+	logBoth.Printf("  Formateada: %s\n", formatted)
+	logBoth.Printf("  Postfija: %s\n", postfix)
 
-		// DFA y minDFA
-		dfaObj := nfa.NFAtoDFA(nfaObj, alphabet)
-		minDFA := nfa.MinimizeDFA(dfaObj)
-
-		// DOT/PNG DFA
-		dfaDotPath := filepath.Join(*dotDir, fmt.Sprintf("dfa_%03d.dot", lineNo))
-		dfaPngPath := filepath.Join(*pngDir, fmt.Sprintf("dfa_%03d.png", lineNo))
-		if err := graphviz.WriteDOTDFA(dfaObj, dfaDotPath); err != nil {
-			logConsole.Printf("  Error DOT DFA: %v\n\n", err)
-		} else {
-			logConsole.Printf("  DOT DFA guardado: %s\n", dfaDotPath)
-			if err := graphviz.GeneratePNGFromDot(dfaDotPath, dfaPngPath); err != nil {
-				logConsole.Printf("  Error PNG DFA: %v\n\n", err)
-			} else {
-				logConsole.Printf("  PNG DFA guardado: %s\n", dfaPngPath)
-			}
-		}
+	// AST
+	ast, err := regex.BuildAST(postfix)
+	if err != nil {
+		logConsole.Printf("  Error de AST: %v\n\n", err)
+		line.Err = fmt.Sprintf("error de AST: %v", err)
+		return res
+	}
+	line.AST = ast
 
-		// DOT/PNG minDFA
-		minDfaDotPath := filepath.Join(*dotDir, fmt.Sprintf("min_dfa_%03d.dot", lineNo))
-		minDfaPngPath := filepath.Join(*pngDir, fmt.Sprintf("min_dfa_%03d.png", lineNo))
-		if err := graphviz.WriteDOTDFA(minDFA, minDfaDotPath); err != nil {
-			logConsole.Printf("  Error DOT DFA minimizado: %v\n\n", err)
-		} else {
-			logConsole.Printf("  DOT DFA minimizado guardado: %s\n", minDfaDotPath)
-			if err := graphviz.GeneratePNGFromDot(minDfaDotPath, minDfaPngPath); err != nil {
-				logConsole.Printf("  Error PNG DFA minimizado: %v\n\n", err)
-			} else {
-				logConsole.Printf("  PNG DFA minimizado guardado: %s\n", minDfaPngPath)
-			}
-		}
+	// NFA (Thompson)
+	nfaObj, err := thompson.Build(ast)
+	if err != nil {
+		logConsole.Printf("  Error de Thompson: %v\n\n", err)
+		line.Err = fmt.Sprintf("error de Thompson: %v", err)
+		return res
+	}
 
-		// ===== Evaluar TODAS las cadenas de la línea =====
-		for i, w := range words {
-			logBoth.Printf("  Caso %d: w = %q\n", i+1, w)
+	// DOT/PNG NFA
+	exportStage(ctx, logConsole, "", fmt.Sprintf("nfa_%03d", lineNo), nfaObj, nil, opts.out)
 
-			acceptedNFA := nfa.Simulate(nfaObj, w)
-			logBoth.Printf("    w ∈ L(NFA)?   %s\n", map[bool]string{true: "sí", false: "no"}[acceptedNFA])
+	// Alfabeto para NFA→DFA: el declarado, o los símbolos de la regex
+	alphabet := sigma
+	if alphabet == nil {
+		alphabet = config.RegexAlphabet(formatted)
+	} else if err := config.ValidateRegexAlphabet(formatted, alphabet); err != nil {
+		logBoth.Printf("  Error de alfabeto: %v\n\n", err)
+		line.Err = fmt.Sprintf("error de alfabeto: %v", err)
+		return res
+	}
+	line.Alphabet = alphabet
+	logBoth.Printf("  Alfabeto: %s\n", config.FormatAlphabet(alphabet))
+
+	// DFA y minDFA
+	dfaObj := nfa.NFAtoDFA(nfaObj, alphabet)
+	// Brzozowski minimiza directamente desde el NFA, sin pasar por el DFA de subconjuntos
+	var minDFA *nfa.DFA
+	if opts.minAlg == nfa.Brzozowski {
+		minDFA = nfa.MinimizeBrzozowski(nfaObj, alphabet)
+	} else {
+		minDFA, err = nfa.MinimizeDFAWith(dfaObj, opts.minAlg)
+	}
+	if err != nil {
+		logBoth.Printf("  Error de minimización: %v\n\n", err)
+		line.Err = fmt.Sprintf("error de minimización: %v", err)
+		return res
+	}
+
+	// Métricas de la regex y de los autómatas (antes de -complete y -trim)
+	metrics := newMetrics(ast, nfaObj, dfaObj, minDFA)
+	line.Metrics = &metrics
+	logBoth.Printf("  Métricas:\n")
+	metrics.write(logBoth.Writer(), "    ")
+
+	// DFAs totales (con estado trampa) o recortados, según las flags
+	if opts.complete {
+		dfaObj = nfa.Complete(dfaObj, alphabet)
+		minDFA = nfa.Complete(minDFA, alphabet)
+	} else if opts.trim {
+		dfaObj = nfa.Trim(dfaObj)
+		minDFA = nfa.Trim(minDFA)
+	}
+
+	line.NFA, line.DFA, line.MinDFA = nfaObj, dfaObj, minDFA
 
-			acceptedDFA := nfa.SimulateDFA(dfaObj, w)
-			logBoth.Printf("    w ∈ L(DFA)?   %s\n", map[bool]string{true: "sí", false: "no"}[acceptedDFA])
+	// DOT/PNG DFA y minDFA
+	exportStage(ctx, logConsole, " DFA", fmt.Sprintf("dfa_%03d", lineNo), dfaObj, nil, opts.out)
+	exportStage(ctx, logConsole, " DFA minimizado", fmt.Sprintf("min_dfa_%03d", lineNo), minDFA, nil, opts.out)
 
-			acceptedMin := nfa.SimulateDFA(minDFA, w)
-			logBoth.Printf("    w ∈ L(minDFA)? %s\n", map[bool]string{true: "sí", false: "no"}[acceptedMin])
+	// ===== Evaluar TODAS las cadenas de la línea =====
+	// El simulador del NFA (por bits si cabe) se construye una vez para todas las cadenas
+	sim := nfa.NewSimulator(nfaObj)
+	for i, tw := range words {
+		w := tw.Word
+		logBoth.Printf("  Caso %d: w = %q\n", i+1, w)
+
+		if sigma != nil {
+			if err := config.ValidateWord(w, alphabet); err != nil {
+				logBoth.Printf("    Error: %v\n", err)
+				line.Words = append(line.Words, reportWord{Word: w, Err: err.Error(), Expect: tw.Expect})
+				if tw.Expect != config.NoExpectation {
+					logBoth.Printf("    Esperado: %s → FALLA\n", yesNo(tw.Expect.Accepts()))
+				}
+				continue
+			}
 		}
 
-		logBoth.Printf("\n")
+		v := nfa.CrossCheckWith(sim, nfaObj, dfaObj, minDFA, w)
+		logBoth.Printf("    w ∈ L(NFA)?   %s\n", yesNo(v.NFA))
+		if v.Sets != v.NFA {
+			logBoth.Printf("    w ∈ L(NFA) por conjuntos? %s\n", yesNo(v.Sets))
+		}
+		logBoth.Printf("    w ∈ L(DFA)?   %s\n", yesNo(v.DFA))
+		logBoth.Printf("    w ∈ L(minDFA)? %s\n", yesNo(v.MinDFA))
+		result := reportWord{Word: w, NFA: v.NFA, Sets: v.Sets, DFA: v.DFA, Min: v.MinDFA, Expect: tw.Expect}
+		line.Words = append(line.Words, result)
+		if opts.verify && !result.Agree() {
+			logBoth.Printf("    ¡Los autómatas no coinciden en esta cadena!\n")
+			res.diverged = true
+		}
+		if tw.Expect != config.NoExpectation {
+			verdict := "correcto"
+			if !checkWord(result, tw.Expect) {
+				verdict = "FALLA"
+			}
+			logBoth.Printf("    Esperado: %s → %s\n", yesNo(tw.Expect.Accepts()), verdict)
+		}
 	}
 
-	if err := sc.Err(); err != nil {
-		log.Fatal(err)
+	// Verificación exhaustiva sobre todas las cadenas cortas
+	if opts.verify {
+		first, checked, diverges := nfa.CrossCheckUpTo(nfaObj, dfaObj, minDFA, alphabet, opts.verifyLen)
+		logBoth.Printf("  Verificación: %s\n", describeCrossCheck(first, checked, diverges, alphabet, opts.verifyLen))
+		res.diverged = res.diverged || diverges
 	}
+
+	logBoth.Printf("\n")
+	return res
 }
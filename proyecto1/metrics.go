@@ -0,0 +1,135 @@
+// /proyecto1/metrics.go
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"proyecto1/nfa"
+	"proyecto1/regex"
+	"proyecto1/thompson"
+)
+
+// Metrics son medidas del tamaño de una regex y de sus autómatas, para estudiar qué regex provocan la
+// explosión de estados del algoritmo de subconjuntos. Los DFAs se miden como los producen NFAtoDFA y la
+// minimización, antes de -complete o -trim.
+type Metrics struct {
+	ASTSize        int `json:"ast_size"`    // Nodos del AST
+	Width          int `json:"width"`       // Ancho alfabético: apariciones de símbolos
+	StarHeight     int `json:"star_height"` // Estrellas anidadas
+	Depth          int `json:"depth"`       // Profundidad del AST
+	NFAStates      int `json:"nfa_states"`
+	NFATransitions int `json:"nfa_transitions"` // Incluye las transiciones ε
+	NFAEpsilon     int `json:"nfa_epsilon"`
+	DFAStates      int `json:"dfa_states"`
+	MinStates      int `json:"min_dfa_states"`
+
+	DFAPerNFA float64 `json:"dfa_nfa_ratio"` // Estados del DFA por estado del NFA
+	MinPerNFA float64 `json:"min_nfa_ratio"` // Estados del DFA minimizado por estado del NFA
+	DFAPerMin float64 `json:"dfa_min_ratio"` // Estados del DFA por estado del DFA minimizado (lo que ahorra minimizar)
+}
+
+// newMetrics mide las etapas; con ast nil (autómata cargado) las medidas de la regex quedan en 0.
+func newMetrics(ast *regex.Node, n *thompson.NFA, dfa, minDFA *nfa.DFA) Metrics {
+	m := Metrics{
+		ASTSize:    ast.Size(),
+		Width:      ast.Width(),
+		StarHeight: ast.StarHeight(),
+		Depth:      ast.Depth(),
+		NFAStates:  len(n.States),
+		DFAStates:  len(dfa.States),
+		MinStates:  len(minDFA.States),
+	}
+	for _, s := range n.States {
+		for sym, targets := range s.Trans {
+			m.NFATransitions += len(targets)
+			if sym == thompson.Epsilon {
+				m.NFAEpsilon += len(targets)
+			}
+		}
+	}
+	m.DFAPerNFA = ratio(m.DFAStates, m.NFAStates)
+	m.MinPerNFA = ratio(m.MinStates, m.NFAStates)
+	m.DFAPerMin = ratio(m.DFAStates, m.MinStates)
+	return m
+}
+
+// ratio retorna a/b, o 0 si b es 0.
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Metrics mide las etapas compiladas.
+func (c *compiled) Metrics() Metrics { return newMetrics(c.AST, c.NFA, c.DFA, c.MinDFA) }
+
+// write escribe las medidas, una por renglón, con el prefijo indicado. Sin AST (autómata cargado) omite
+// las medidas de la regex.
+func (m Metrics) write(w io.Writer, prefix string) {
+	if m.ASTSize > 0 {
+		fmt.Fprintf(w, "%sTamaño del AST: %d (ancho alfabético %d, altura de estrella %d, profundidad %d)\n", prefix, m.ASTSize, m.Width, m.StarHeight, m.Depth)
+	}
+	fmt.Fprintf(w, "%sNFA: %d estados, %d transiciones (%d ε)\n", prefix, m.NFAStates, m.NFATransitions, m.NFAEpsilon)
+	fmt.Fprintf(w, "%sDFA: %d estados (%.2f por estado del NFA)\n", prefix, m.DFAStates, m.DFAPerNFA)
+	fmt.Fprintf(w, "%sDFA minimizado: %d estados (%.2f por estado del NFA, DFA %.2f veces mayor)\n", prefix, m.MinStates, m.MinPerNFA, m.DFAPerMin)
+}
+
+// metricsRow son las medidas de una línea de la entrada, para el CSV de -metrics.
+type metricsRow struct {
+	LineNo int
+	Regex  string
+	Metrics
+}
+
+// metricsHeader son las columnas del CSV de -metrics.
+var metricsHeader = []string{
+	"line", "regex", "ast_size", "width", "star_height", "depth",
+	"nfa_states", "nfa_transitions", "nfa_epsilon", "dfa_states", "min_dfa_states",
+	"dfa_nfa_ratio", "min_nfa_ratio", "dfa_min_ratio",
+}
+
+// writeMetricsCSV guarda las medidas de todas las líneas en un CSV, una fila por línea.
+func writeMetricsCSV(path string, rows []metricsRow) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write(metricsHeader)
+	for _, r := range rows {
+		m := r.Metrics
+		record := []string{strconv.Itoa(r.LineNo), r.Regex}
+		for _, v := range []int{m.ASTSize, m.Width, m.StarHeight, m.Depth, m.NFAStates, m.NFATransitions, m.NFAEpsilon, m.DFAStates, m.MinStates} {
+			record = append(record, strconv.Itoa(v))
+		}
+		for _, v := range []float64{m.DFAPerNFA, m.MinPerNFA, m.DFAPerMin} {
+			record = append(record, strconv.FormatFloat(v, 'f', 3, 64))
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// largestBlowup retorna la fila con más estados del DFA por estado del NFA (la primera, si hay empate).
+func largestBlowup(rows []metricsRow) (metricsRow, bool) {
+	if len(rows) == 0 {
+		return metricsRow{}, false
+	}
+	best := rows[0]
+	for _, r := range rows[1:] {
+		if r.DFAPerNFA > best.DFAPerNFA {
+			best = r
+		}
+	}
+	return best, true
+}
@@ -0,0 +1,75 @@
+// /proyecto1/metrics_test.go
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"proyecto1/nfa"
+)
+
+func TestNewMetrics(t *testing.T) {
+	c, err := compilePipeline("(a|b)*abb", nil, nfa.Hopcroft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Metrics{
+		ASTSize: 10, Width: 5, StarHeight: 1, Depth: 6,
+		NFAStates: 14, NFATransitions: 16, NFAEpsilon: 11,
+		DFAStates: 5, MinStates: 4,
+		DFAPerNFA: 5.0 / 14, MinPerNFA: 4.0 / 14, DFAPerMin: 5.0 / 4,
+	}
+	if got := c.Metrics(); got != want {
+		t.Errorf("Metrics = %+v, se esperaba %+v", got, want)
+	}
+
+	// Sin AST (autómata cargado) las medidas de la regex quedan en 0
+	if m := newMetrics(nil, c.NFA, c.DFA, c.MinDFA); m.ASTSize != 0 || m.Width != 0 || m.StarHeight != 0 || m.Depth != 0 || m.NFAStates != 14 {
+		t.Errorf("newMetrics sin AST = %+v", m)
+	}
+}
+
+func TestWriteMetricsCSV(t *testing.T) {
+	rows := []metricsRow{
+		{LineNo: 1, Regex: "a,b", Metrics: Metrics{ASTSize: 3, Width: 2, Depth: 2, NFAStates: 6, NFATransitions: 6, NFAEpsilon: 4, DFAStates: 3, MinStates: 2, DFAPerNFA: 0.5, MinPerNFA: 1.0 / 3, DFAPerMin: 1.5}},
+		{LineNo: 3, Regex: "ε"},
+	}
+	path := filepath.Join(t.TempDir(), "m.csv")
+	if err := writeMetricsCSV(path, rows); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"line", "regex", "ast_size", "width", "star_height", "depth", "nfa_states", "nfa_transitions", "nfa_epsilon", "dfa_states", "min_dfa_states", "dfa_nfa_ratio", "min_nfa_ratio", "dfa_min_ratio"},
+		{"1", "a,b", "3", "2", "0", "2", "6", "6", "4", "3", "2", "0.500", "0.333", "1.500"},
+		{"3", "ε", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0.000", "0.000", "0.000"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("CSV:\n%q\nse esperaba:\n%q", records, want)
+	}
+}
+
+func TestLargestBlowup(t *testing.T) {
+	rows := []metricsRow{
+		{LineNo: 1, Metrics: Metrics{DFAPerNFA: 0.5}},
+		{LineNo: 2, Metrics: Metrics{DFAPerNFA: 2}},
+		{LineNo: 3, Metrics: Metrics{DFAPerNFA: 2}},
+	}
+	if r, ok := largestBlowup(rows); !ok || r.LineNo != 2 {
+		t.Errorf("largestBlowup = línea %d (%v), se esperaba la 2", r.LineNo, ok)
+	}
+	if _, ok := largestBlowup(nil); ok {
+		t.Errorf("largestBlowup sin filas retornó una")
+	}
+}
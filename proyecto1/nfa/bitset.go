@@ -0,0 +1,146 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la simulación de NFAs con conjuntos de estados representados como bits.
+package nfa
+
+import (
+	"fmt"
+	"math/bits"
+
+	"proyecto1/thompson"
+)
+
+// DefaultBitsetWords es la cantidad de palabras de 64 bits por defecto de un BitNFA: NewSimulator usa
+// la simulación por bits en los NFAs de hasta 64·DefaultBitsetWords estados.
+const DefaultBitsetWords = 4
+
+// bitset es un conjunto de estados del NFA: el bit i indica si está el estado de índice i.
+type bitset []uint64
+
+// or agrega al conjunto los estados de o.
+func (b bitset) or(o bitset) {
+	for w := range b {
+		b[w] |= o[w]
+	}
+}
+
+// has indica si el estado de índice i está en el conjunto.
+func (b bitset) has(i int) bool { return b[i/64]&(1<<(i%64)) != 0 }
+
+// clear vacía el conjunto.
+func (b bitset) clear() {
+	for w := range b {
+		b[w] = 0
+	}
+}
+
+// empty indica si el conjunto está vacío.
+func (b bitset) empty() bool {
+	for _, w := range b {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BitNFA simula un NFA con los conjuntos de estados como máscaras de bits en lugar del stateSet de
+// Simulate. Las clausuras ε se precalculan: para cada símbolo y cada estado i, trans[sym][i] es la
+// clausura ε de δ(i, sym), así que un paso es un OR de las máscaras de los estados activos.
+// BitNFA implementa Runner.
+type BitNFA struct {
+	start     bitset            // Clausura ε del estado inicial
+	accept    int               // Índice del estado de aceptación
+	trans     map[rune][]bitset // trans[sym][i]: clausura ε de δ(i, sym); nil si i no tiene transición con sym
+	cur, next bitset
+}
+
+// NewBitNFA prepara la simulación por bits del NFA con conjuntos de maxWords palabras de 64 bits
+// (DefaultBitsetWords si maxWords <= 0). Falla si el NFA tiene más de 64·maxWords estados.
+func NewBitNFA(n *thompson.NFA, maxWords int) (*BitNFA, error) {
+	if maxWords <= 0 {
+		maxWords = DefaultBitsetWords
+	}
+	states := sortedStates(n)
+	if len(states) > 64*maxWords {
+		return nil, fmt.Errorf("el NFA tiene %d estados; la simulación por bits admite hasta %d", len(states), 64*maxWords)
+	}
+	words := (len(states) + 63) / 64
+	index := make(map[*thompson.State]int, len(states))
+	for i, s := range states {
+		index[s] = i
+	}
+
+	// Clausura ε de cada estado
+	closure := make([]bitset, len(states))
+	for i, s := range states {
+		closure[i] = make(bitset, words)
+		for t := range epsilonClosure(stateSet{s: {}}) {
+			j := index[t]
+			closure[i][j/64] |= 1 << (j % 64)
+		}
+	}
+
+	b := &BitNFA{accept: index[n.Accept], trans: map[rune][]bitset{}, cur: make(bitset, words), next: make(bitset, words)}
+	b.start = closure[index[n.Start]]
+	for i, s := range states {
+		for _, sym := range sortedSymbols(s.Trans) {
+			if sym == thompson.Epsilon {
+				continue
+			}
+			masks := b.trans[sym]
+			if masks == nil {
+				masks = make([]bitset, len(states))
+				b.trans[sym] = masks
+			}
+			if masks[i] == nil {
+				masks[i] = make(bitset, words)
+			}
+			for _, t := range s.Trans[sym] {
+				masks[i].or(closure[index[t]])
+			}
+		}
+	}
+	b.Reset()
+	return b, nil
+}
+
+// NewSimulator elige la simulación del NFA según su cantidad de estados: por bits (BitNFA) si caben en
+// DefaultBitsetWords palabras, o por conjuntos (NFARunner) si no.
+func NewSimulator(n *thompson.NFA) Runner {
+	if b, err := NewBitNFA(n, DefaultBitsetWords); err == nil {
+		return b
+	}
+	return NewNFARunner(n)
+}
+
+// Reset vuelve a la clausura ε del estado inicial.
+func (b *BitNFA) Reset() { copy(b.cur, b.start) }
+
+// Step consume un símbolo.
+func (b *BitNFA) Step(sym rune) {
+	b.cur, b.next = b.step(b.cur, sym, b.next), b.cur
+}
+
+// step escribe en out los estados alcanzados desde cur con sym (clausura ε incluida) y lo retorna.
+func (b *BitNFA) step(cur bitset, sym rune, out bitset) bitset {
+	out.clear()
+	masks := b.trans[sym]
+	if masks == nil {
+		return out
+	}
+	for w, word := range cur {
+		for word != 0 {
+			if m := masks[w*64+bits.TrailingZeros64(word)]; m != nil {
+				out.or(m)
+			}
+			word &= word - 1
+		}
+	}
+	return out
+}
+
+// Accepting indica si el estado de aceptación está entre los activos.
+func (b *BitNFA) Accepting() bool { return b.cur.has(b.accept) }
+
+// Dead indica si no quedan estados activos.
+func (b *BitNFA) Dead() bool { return b.cur.empty() }
@@ -0,0 +1,70 @@
+package nfa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBitNFAMatchesSimulate(t *testing.T) {
+	tests := []struct {
+		regex string
+		words int // Longitud máxima de las cadenas evaluadas
+	}{
+		{"a(b|c)*", 5},
+		{"(a|ε)b*", 5},
+		{"(a|b)*abb", 6},
+		{"ε", 3},
+		{"(a*|b*)+", 6},
+		{"(a|b)*a" + strings.Repeat("(a|b)", 8), 10},   // Más de 64 estados: varias palabras
+		{"(ab|ba)*" + strings.Repeat("(a|bb)", 10), 8}, // Más de 64 estados: varias palabras
+	}
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			n := buildNFA(t, tt.regex)
+			b, err := NewBitNFA(n, DefaultBitsetWords)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sim := NewSimulator(n)
+			if _, ok := sim.(*BitNFA); !ok {
+				t.Errorf("NewSimulator no eligió BitNFA para %d estados", len(n.States))
+			}
+			for _, w := range allWords(Alphabet(n), tt.words) {
+				want := Simulate(n, w)
+				if got := Run(b, w); got != want {
+					t.Errorf("%q: BitNFA %v, Simulate %v", w, got, want)
+				}
+				if got := Run(sim, w); got != want {
+					t.Errorf("%q: NewSimulator %v, Simulate %v", w, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSimulatorFallsBackToSets(t *testing.T) {
+	n := buildNFA(t, "(a|b)*a"+strings.Repeat("(a|b)", 60))
+	if _, err := NewBitNFA(n, DefaultBitsetWords); err == nil {
+		t.Fatalf("NewBitNFA aceptó un NFA de %d estados", len(n.States))
+	}
+	sim := NewSimulator(n)
+	if _, ok := sim.(*NFARunner); !ok {
+		t.Fatalf("NewSimulator eligió %T para %d estados", sim, len(n.States))
+	}
+	for _, w := range []string{"", "a", "a" + strings.Repeat("b", 40), strings.Repeat("ab", 25)} {
+		if got, want := Run(sim, w), Simulate(n, w); got != want {
+			t.Errorf("%q: NFARunner %v, Simulate %v", w, got, want)
+		}
+	}
+}
+
+func TestCrossCheckUpToAgrees(t *testing.T) {
+	for _, r := range []string{"a(b|c)*", "(a|b)*abb", "(a|ε)(b|c)*a"} {
+		n := buildNFA(t, r)
+		alphabet := Alphabet(n)
+		dfa := NFAtoDFA(n, alphabet)
+		if first, _, diverges := CrossCheckUpTo(n, dfa, MinimizeDFA(dfa), alphabet, 6); diverges {
+			t.Errorf("%s: los autómatas no coinciden en %+v", r, first)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la numeración canónica de DFAs y la comparación por isomorfismo.
+package nfa
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Canonical retorna una copia del DFA con los estados renombrados q0, q1, ... en orden BFS desde
+// el estado inicial, recorriendo los símbolos del alfabeto en orden ascendente.
+// Solo conserva los estados alcanzables. Dos DFAs isomorfos con el mismo alfabeto producen
+// exactamente el mismo resultado, por lo que sus archivos DOT son idénticos.
+func Canonical(dfa *DFA) *DFA {
+	alphabet := sortedAlphabet(dfa.Alphabet)
+
+	names := map[string]string{dfa.Start: "q0"}
+	order := []string{dfa.Start}
+	for i := 0; i < len(order); i++ {
+		for _, sym := range alphabet {
+			next, ok := dfa.Transitions[order[i]][sym]
+			if !ok {
+				continue
+			}
+			if _, seen := names[next]; !seen {
+				names[next] = fmt.Sprintf("q%d", len(order))
+				order = append(order, next)
+			}
+		}
+	}
+
+	result := &DFA{
+		States:      make([]string, 0, len(order)),
+		Alphabet:    alphabet,
+		Transitions: make(map[string]map[rune]string, len(order)),
+		Start:       "q0",
+		Accepting:   map[string]bool{},
+	}
+	for _, state := range order {
+		name := names[state]
+		result.States = append(result.States, name)
+		row := map[rune]string{}
+		for _, sym := range alphabet {
+			if next, ok := dfa.Transitions[state][sym]; ok {
+				row[sym] = names[next]
+			}
+		}
+		result.Transitions[name] = row
+		if dfa.Accepting[state] {
+			result.Accepting[name] = true
+		}
+	}
+	return result
+}
+
+// Isomorphic verifica si los DFAs a y b son iguales salvo por el nombre de sus estados.
+// Recorre ambos autómatas en paralelo desde el estado inicial (BFS) y, si son isomorfos,
+// retorna la biyección estado de a → estado de b. Solo considera estados alcanzables.
+func Isomorphic(a, b *DFA) (map[string]string, bool) {
+	if a == nil || b == nil {
+		return nil, a == b
+	}
+	symbols := sortedAlphabet(append(append([]rune{}, a.Alphabet...), b.Alphabet...))
+
+	aToB := map[string]string{a.Start: b.Start}
+	bToA := map[string]string{b.Start: a.Start}
+	queue := []string{a.Start}
+
+	for len(queue) > 0 {
+		sa := queue[0]
+		queue = queue[1:]
+		sb := aToB[sa]
+
+		if a.Accepting[sa] != b.Accepting[sb] {
+			return nil, false
+		}
+		for _, sym := range symbols {
+			na, okA := a.Transitions[sa][sym]
+			nb, okB := b.Transitions[sb][sym]
+			if okA != okB {
+				return nil, false
+			}
+			if !okA {
+				continue
+			}
+			mb, seenA := aToB[na]
+			ma, seenB := bToA[nb]
+			if seenA != seenB {
+				return nil, false
+			}
+			if seenA {
+				if mb != nb || ma != na {
+					return nil, false
+				}
+				continue
+			}
+			aToB[na] = nb
+			bToA[nb] = na
+			queue = append(queue, na)
+		}
+	}
+	return aToB, true
+}
+
+// sortedAlphabet retorna una copia ordenada y sin repetidos del alfabeto.
+func sortedAlphabet(alphabet []rune) []rune {
+	out := append([]rune{}, alphabet...)
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	dedup := out[:0]
+	for i, sym := range out {
+		if i == 0 || sym != out[i-1] {
+			dedup = append(dedup, sym)
+		}
+	}
+	return dedup
+}
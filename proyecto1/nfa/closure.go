@@ -0,0 +1,365 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo las propiedades de cerradura de los lenguajes regulares como operaciones sobre autómatas.
+package nfa
+
+import (
+	"proyecto1/config"
+	"proyecto1/thompson"
+)
+
+// Automaton es cualquier autómata finito convertible a NFA: *thompson.NFA y *DFA lo implementan.
+// Las operaciones de cerradura aceptan ambos tipos.
+type Automaton interface {
+	AsNFA() *thompson.NFA
+}
+
+// -------------------------- Operaciones sobre NFA --------------------------
+
+// nfaBuilder crea estados con IDs consecutivos para construir NFAs nuevos a partir de otros.
+type nfaBuilder struct {
+	next   int
+	states []*thompson.State
+}
+
+// newState crea un estado nuevo con un ID único dentro del builder.
+func (b *nfaBuilder) newState() *thompson.State {
+	s := thompson.NewState(b.next)
+	b.next++
+	b.states = append(b.states, s)
+	return s
+}
+
+// clone copia el autómata con IDs nuevos. Retorna la copia de cada estado original
+// (indexada por el estado original) junto con el NFA original.
+func (b *nfaBuilder) clone(a Automaton) (*thompson.NFA, map[*thompson.State]*thompson.State) {
+	n := a.AsNFA()
+	copies := make(map[*thompson.State]*thompson.State, len(n.States))
+	states := sortedStates(n)
+	for _, s := range states {
+		copies[s] = b.newState()
+	}
+	for _, s := range states {
+		for _, sym := range sortedSymbols(s.Trans) {
+			for _, t := range s.Trans[sym] {
+				copies[s].AddEdge(sym, copies[t])
+			}
+		}
+	}
+	return n, copies
+}
+
+// build retorna el NFA con todos los estados creados por el builder.
+func (b *nfaBuilder) build(start, accept *thompson.State) *thompson.NFA {
+	return &thompson.NFA{Start: start, Accept: accept, States: b.states}
+}
+
+// Union construye un NFA para L(a) ∪ L(b) (construcción de Thompson).
+func Union(a, b Automaton) *thompson.NFA {
+	bld := &nfaBuilder{}
+	s := bld.newState()
+	na, ca := bld.clone(a)
+	nb, cb := bld.clone(b)
+	t := bld.newState()
+	s.AddEdge(thompson.Epsilon, ca[na.Start])
+	s.AddEdge(thompson.Epsilon, cb[nb.Start])
+	ca[na.Accept].AddEdge(thompson.Epsilon, t)
+	cb[nb.Accept].AddEdge(thompson.Epsilon, t)
+	return bld.build(s, t)
+}
+
+// Concat construye un NFA para L(a)·L(b) (construcción de Thompson).
+func Concat(a, b Automaton) *thompson.NFA {
+	bld := &nfaBuilder{}
+	na, ca := bld.clone(a)
+	nb, cb := bld.clone(b)
+	ca[na.Accept].AddEdge(thompson.Epsilon, cb[nb.Start])
+	return bld.build(ca[na.Start], cb[nb.Accept])
+}
+
+// Star construye un NFA para L(a)* (construcción de Thompson).
+func Star(a Automaton) *thompson.NFA {
+	bld := &nfaBuilder{}
+	s := bld.newState()
+	na, ca := bld.clone(a)
+	t := bld.newState()
+	s.AddEdge(thompson.Epsilon, ca[na.Start])
+	s.AddEdge(thompson.Epsilon, t)
+	ca[na.Accept].AddEdge(thompson.Epsilon, ca[na.Start])
+	ca[na.Accept].AddEdge(thompson.Epsilon, t)
+	return bld.build(s, t)
+}
+
+// Prefix construye un NFA para los prefijos de L(a): { x | ∃y: xy ∈ L(a) }.
+// Todo estado útil (alcanzable y co-alcanzable) se conecta con ε a un nuevo estado de aceptación.
+func Prefix(a Automaton) *thompson.NFA {
+	return infixClosure(a, false, true)
+}
+
+// Suffix construye un NFA para los sufijos de L(a): { y | ∃x: xy ∈ L(a) }.
+// Un nuevo estado inicial se conecta con ε a todo estado útil.
+func Suffix(a Automaton) *thompson.NFA {
+	return infixClosure(a, true, false)
+}
+
+// Infix construye un NFA para las subcadenas (factores) de L(a): { y | ∃x,z: xyz ∈ L(a) }.
+func Infix(a Automaton) *thompson.NFA {
+	return infixClosure(a, true, true)
+}
+
+// infixClosure implementa Prefix, Suffix e Infix: agrega un inicio nuevo (newStart) y/o una
+// aceptación nueva (newAccept) conectados con ε a los estados útiles del autómata.
+func infixClosure(a Automaton, newStart, newAccept bool) *thompson.NFA {
+	bld := &nfaBuilder{}
+	var s *thompson.State
+	if newStart {
+		s = bld.newState()
+	}
+	n, copies := bld.clone(a)
+	useful := usefulStates(n)
+
+	start, accept := copies[n.Start], copies[n.Accept]
+	if newStart {
+		for _, st := range sortedStates(n) {
+			if useful[st] {
+				s.AddEdge(thompson.Epsilon, copies[st])
+			}
+		}
+		start = s
+	}
+	if newAccept {
+		t := bld.newState()
+		for _, st := range sortedStates(n) {
+			if useful[st] {
+				copies[st].AddEdge(thompson.Epsilon, t)
+			}
+		}
+		accept = t
+	}
+	return bld.build(start, accept)
+}
+
+// usefulStates retorna los estados alcanzables desde el inicio y desde los que se alcanza la aceptación.
+func usefulStates(n *thompson.NFA) map[*thompson.State]bool {
+	forward := map[*thompson.State]bool{n.Start: true}
+	stack := []*thompson.State{n.Start}
+	pred := map[*thompson.State][]*thompson.State{}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, outs := range s.Trans {
+			for _, t := range outs {
+				pred[t] = append(pred[t], s)
+				if !forward[t] {
+					forward[t] = true
+					stack = append(stack, t)
+				}
+			}
+		}
+	}
+
+	useful := map[*thompson.State]bool{}
+	if !forward[n.Accept] {
+		return useful
+	}
+	useful[n.Accept] = true
+	stack = append(stack, n.Accept)
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, p := range pred[s] {
+			if !useful[p] {
+				useful[p] = true
+				stack = append(stack, p)
+			}
+		}
+	}
+	return useful
+}
+
+// -------------------------- Operaciones sobre DFA (producto) --------------------------
+
+// toDFA obtiene un DFA total sobre el alfabeto dado a partir de cualquier autómata.
+func toDFA(a Automaton, alphabet []rune) *DFA {
+	d, ok := a.(*DFA)
+	if !ok {
+		d = NFAtoDFA(a.AsNFA(), alphabet)
+	}
+	return Complete(d, alphabet)
+}
+
+// completePair convierte ambos autómatas en DFAs totales sobre el mismo alfabeto
+// (el dado, unido a los alfabetos de los DFAs de entrada).
+func completePair(a, b Automaton, alphabet []rune) (*DFA, *DFA) {
+	da := toDFA(a, alphabet)
+	db := toDFA(b, alphabet)
+	sigma := append([]rune{}, da.Alphabet...)
+	for _, sym := range db.Alphabet {
+		if !config.ContainsRune(sigma, sym) {
+			sigma = append(sigma, sym)
+		}
+	}
+	return Complete(da, sigma), Complete(db, sigma)
+}
+
+// pairName nombra el estado (p, q) del autómata producto.
+func pairName(p, q string) string {
+	return "(" + p + "," + q + ")"
+}
+
+// product construye el DFA producto de a y b; un par es de aceptación según accept(p ∈ F₁, q ∈ F₂).
+func product(a, b Automaton, alphabet []rune, accept func(inA, inB bool) bool) *DFA {
+	da, db := completePair(a, b, alphabet)
+
+	type pair struct{ p, q string }
+	startName := pairName(da.Start, db.Start)
+	result := &DFA{
+		States:      []string{startName},
+		Alphabet:    da.Alphabet,
+		Transitions: map[string]map[rune]string{},
+		Start:       startName,
+		Accepting:   map[string]bool{},
+	}
+	seen := map[string]bool{startName: true}
+	queue := []pair{{da.Start, db.Start}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		name := pairName(cur.p, cur.q)
+		if accept(da.Accepting[cur.p], db.Accepting[cur.q]) {
+			result.Accepting[name] = true
+		}
+		row := map[rune]string{}
+		for _, sym := range result.Alphabet {
+			next := pair{da.Transitions[cur.p][sym], db.Transitions[cur.q][sym]}
+			nextName := pairName(next.p, next.q)
+			if !seen[nextName] {
+				seen[nextName] = true
+				result.States = append(result.States, nextName)
+				queue = append(queue, next)
+			}
+			row[sym] = nextName
+		}
+		result.Transitions[name] = row
+	}
+	return result
+}
+
+// Intersection construye el DFA producto para L(a) ∩ L(b) sobre el alfabeto dado.
+func Intersection(a, b Automaton, alphabet []rune) *DFA {
+	return product(a, b, alphabet, func(inA, inB bool) bool { return inA && inB })
+}
+
+// Difference construye el DFA producto para L(a) \ L(b) sobre el alfabeto dado.
+func Difference(a, b Automaton, alphabet []rune) *DFA {
+	return product(a, b, alphabet, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// SymmetricDifference construye el DFA producto para L(a) △ L(b) sobre el alfabeto dado.
+func SymmetricDifference(a, b Automaton, alphabet []rune) *DFA {
+	return product(a, b, alphabet, func(inA, inB bool) bool { return inA != inB })
+}
+
+// Complement construye un DFA para Σ* \ L(a): completa el DFA sobre Σ e invierte la aceptación.
+func Complement(a Automaton, alphabet []rune) *DFA {
+	d := toDFA(a, alphabet)
+	for _, state := range d.States {
+		d.Accepting[state] = !d.Accepting[state]
+		if !d.Accepting[state] {
+			delete(d.Accepting, state)
+		}
+	}
+	return d
+}
+
+// RightQuotient construye un DFA para el cociente derecho L(a)/L(b) = { x | ∃y ∈ L(b): xy ∈ L(a) }.
+// Es el DFA de a con aceptación en los estados p tales que, en el producto, (p, inicio de b)
+// alcanza un par de aceptación de ambos.
+func RightQuotient(a, b Automaton, alphabet []rune) *DFA {
+	da, db := completePair(a, b, alphabet)
+
+	// Predecesores en el grafo producto (completo) de da × db
+	type pair struct{ p, q string }
+	pred := map[pair][]pair{}
+	for _, p := range da.States {
+		for _, q := range db.States {
+			for _, sym := range da.Alphabet {
+				next := pair{da.Transitions[p][sym], db.Transitions[q][sym]}
+				pred[next] = append(pred[next], pair{p, q})
+			}
+		}
+	}
+
+	// Pares desde los que se alcanza F₁ × F₂ (BFS hacia atrás)
+	good := map[pair]bool{}
+	queue := []pair{}
+	for _, p := range da.States {
+		for _, q := range db.States {
+			if da.Accepting[p] && db.Accepting[q] {
+				good[pair{p, q}] = true
+				queue = append(queue, pair{p, q})
+			}
+		}
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, prev := range pred[cur] {
+			if !good[prev] {
+				good[prev] = true
+				queue = append(queue, prev)
+			}
+		}
+	}
+
+	result := copyDFA(da)
+	result.Accepting = map[string]bool{}
+	for _, p := range da.States {
+		if good[pair{p, db.Start}] {
+			result.Accepting[p] = true
+		}
+	}
+	return result
+}
+
+// LeftQuotient construye un NFA para el cociente izquierdo L(b)\L(a) = { y | ∃x ∈ L(b): xy ∈ L(a) }.
+// Es el DFA de a con un nuevo estado inicial conectado con ε a cada estado p tal que, en el
+// producto, se alcanza (p, f) con f de aceptación en b.
+func LeftQuotient(a, b Automaton, alphabet []rune) *thompson.NFA {
+	da, db := completePair(a, b, alphabet)
+
+	// Pares alcanzables desde (inicio de a, inicio de b)
+	type pair struct{ p, q string }
+	seen := map[pair]bool{{da.Start, db.Start}: true}
+	queue := []pair{{da.Start, db.Start}}
+	starts := map[string]bool{}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if db.Accepting[cur.q] {
+			starts[cur.p] = true
+		}
+		for _, sym := range da.Alphabet {
+			next := pair{da.Transitions[cur.p][sym], db.Transitions[cur.q][sym]}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// FromDFA asigna a cada estado el ID de su posición en da.States
+	bld := &nfaBuilder{}
+	s := bld.newState()
+	n, copies := bld.clone(FromDFA(da))
+	byID := make(map[int]*thompson.State, len(n.States))
+	for orig, cp := range copies {
+		byID[orig.ID] = cp
+	}
+	for i, p := range da.States {
+		if starts[p] {
+			s.AddEdge(thompson.Epsilon, byID[i])
+		}
+	}
+	return bld.build(s, copies[n.Accept])
+}
@@ -0,0 +1,88 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la completación de DFAs con estado trampa.
+package nfa
+
+import "proyecto1/config"
+
+// TrapState es el nombre del estado trampa que agrega Complete (el subconjunto vacío).
+const TrapState = "∅"
+
+// Complete retorna una copia total del DFA sobre el alfabeto dado (unido al del DFA):
+// toda transición faltante se dirige a un estado trampa de no aceptación que se cicla a sí mismo.
+// Si el DFA ya es total no se agrega ningún estado.
+func Complete(dfa *DFA, alphabet []rune) *DFA {
+	result := copyDFA(dfa)
+	for _, sym := range alphabet {
+		if !config.ContainsRune(result.Alphabet, sym) {
+			result.Alphabet = append(result.Alphabet, sym)
+		}
+	}
+
+	// Nombre del estado trampa, evitando choques con estados existentes
+	trap := TrapState
+	for {
+		if _, exists := result.Transitions[trap]; !exists && !containsState(result.States, trap) {
+			break
+		}
+		trap += "'"
+	}
+
+	needsTrap := false
+	for _, state := range result.States {
+		row := result.Transitions[state]
+		if row == nil {
+			row = map[rune]string{}
+			result.Transitions[state] = row
+		}
+		for _, sym := range result.Alphabet {
+			if _, ok := row[sym]; !ok {
+				row[sym] = trap
+				needsTrap = true
+			}
+		}
+	}
+
+	if needsTrap {
+		result.States = append(result.States, trap)
+		row := make(map[rune]string, len(result.Alphabet))
+		for _, sym := range result.Alphabet {
+			row[sym] = trap
+		}
+		result.Transitions[trap] = row
+	}
+	return result
+}
+
+// copyDFA retorna una copia profunda del DFA.
+func copyDFA(dfa *DFA) *DFA {
+	result := &DFA{
+		States:      append([]string{}, dfa.States...),
+		Alphabet:    append([]rune{}, dfa.Alphabet...),
+		Transitions: make(map[string]map[rune]string, len(dfa.Transitions)),
+		Start:       dfa.Start,
+		Accepting:   make(map[string]bool, len(dfa.Accepting)),
+	}
+	for state, row := range dfa.Transitions {
+		newRow := make(map[rune]string, len(row))
+		for sym, to := range row {
+			newRow[sym] = to
+		}
+		result.Transitions[state] = newRow
+	}
+	for state, ok := range dfa.Accepting {
+		if ok {
+			result.Accepting[state] = true
+		}
+	}
+	return result
+}
+
+// containsState verifica si la lista de estados contiene el estado.
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
@@ -88,9 +88,6 @@ func NFAtoDFA(nfa *thompson.NFA, alphabet []rune) *DFA {
 		dfaAccepting[startName] = true
 	}
 
-	subsetNames := map[string]string{} // nombre del conjunto → letra (no se usa en la construcción final)
-	letters := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
-
 	// Algoritmo principal: construcción del DFA por subconjuntos
 	for len(queue) > 0 {
 		currentSet := queue[0]
@@ -118,9 +115,6 @@ func NFAtoDFA(nfa *thompson.NFA, alphabet []rune) *DFA {
 			}
 			dfaTransitions[currentName][sym] = nextName
 		}
-		// Asigna una letra al conjunto (solo para visualización, no se usa en el DFA final)
-		idx := len(subsetNames)
-		subsetNames[setName(currentSet)] = string(letters[idx])
 	}
 
 	// Retorna el DFA construido
@@ -0,0 +1,44 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la enumeración de las cadenas de un lenguaje en orden canónico.
+package nfa
+
+// Enumerate retorna las cadenas aceptadas por el DFA en orden canónico (por longitud y, entre las de igual
+// longitud, en orden lexicográfico), hasta limit cadenas y de longitud a lo más maxLen. Un límite negativo
+// o cero en limit, o negativo en maxLen, significa sin límite; si el lenguaje es infinito, al menos uno de
+// los dos debe estar acotado. El segundo valor es true si se enumeraron todas las cadenas del lenguaje.
+func Enumerate(dfa *DFA, maxLen, limit int) ([]string, bool) {
+	// Sin estados muertos, todo prefijo en la cola se extiende a alguna cadena aceptada
+	trimmed := Trim(dfa)
+	alphabet := sortedAlphabet(trimmed.Alphabet)
+
+	type prefix struct {
+		state string
+		word  []rune
+	}
+	words := []string{}
+	complete := true
+	queue := []prefix{{state: trimmed.Start}}
+	for len(queue) > 0 {
+		if limit > 0 && len(words) == limit {
+			return words, false
+		}
+		cur := queue[0]
+		queue = queue[1:]
+		if trimmed.Accepting[cur.state] {
+			words = append(words, string(cur.word))
+		}
+		for _, sym := range alphabet {
+			next, ok := trimmed.Transitions[cur.state][sym]
+			if !ok {
+				continue
+			}
+			if maxLen >= 0 && len(cur.word) == maxLen {
+				complete = false
+				break
+			}
+			word := append(append(make([]rune, 0, len(cur.word)+1), cur.word...), sym)
+			queue = append(queue, prefix{state: next, word: word})
+		}
+	}
+	return words, complete
+}
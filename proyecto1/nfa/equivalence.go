@@ -0,0 +1,49 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la verificación de equivalencia de lenguajes con una cadena testigo.
+package nfa
+
+// ShortestWord retorna la cadena más corta aceptada por el DFA (la menor en orden lexicográfico entre
+// las de esa longitud) recorriendo los estados en anchura. El segundo valor es false si L(dfa) es vacío.
+func ShortestWord(dfa *DFA) (string, bool) {
+	alphabet := sortedAlphabet(dfa.Alphabet)
+	type visit struct {
+		prev string
+		sym  rune
+	}
+	parent := map[string]visit{dfa.Start: {}}
+	queue := []string{dfa.Start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if dfa.Accepting[cur] {
+			// Reconstruir la cadena desde el estado inicial
+			var word []rune
+			for s := cur; s != dfa.Start; s = parent[s].prev {
+				word = append(word, parent[s].sym)
+			}
+			for i, j := 0, len(word)-1; i < j; i, j = i+1, j-1 {
+				word[i], word[j] = word[j], word[i]
+			}
+			return string(word), true
+		}
+		for _, sym := range alphabet {
+			next, ok := dfa.Transitions[cur][sym]
+			if !ok {
+				continue
+			}
+			if _, seen := parent[next]; !seen {
+				parent[next] = visit{prev: cur, sym: sym}
+				queue = append(queue, next)
+			}
+		}
+	}
+	return "", false
+}
+
+// Equivalent indica si L(a) = L(b) sobre el alfabeto dado (unido a los alfabetos de los DFAs).
+// Si no son equivalentes, retorna también la cadena más corta que pertenece a exactamente uno
+// de los dos lenguajes (ver ShortestWord sobre la diferencia simétrica).
+func Equivalent(a, b Automaton, alphabet []rune) (bool, string) {
+	witness, found := ShortestWord(SymmetricDifference(a, b, alphabet))
+	return !found, witness
+}
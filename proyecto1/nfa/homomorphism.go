@@ -0,0 +1,160 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo homomorfismos, homomorfismos inversos y sustituciones por lenguajes regulares.
+package nfa
+
+import (
+	"fmt"
+	"proyecto1/config"
+	"proyecto1/thompson"
+	"sort"
+	"unicode/utf8"
+)
+
+// Homomorphism asigna a cada símbolo del alfabeto de origen una cadena (posiblemente vacía).
+type Homomorphism map[rune]string
+
+// Substitution asigna a cada símbolo del alfabeto de origen un lenguaje regular, dado por un autómata.
+type Substitution map[rune]Automaton
+
+// Alphabet retorna los símbolos (sin ε) que aparecen en las transiciones del autómata, ordenados.
+// Para un DFA retorna una copia ordenada de su alfabeto.
+func Alphabet(a Automaton) []rune {
+	if d, ok := a.(*DFA); ok {
+		return sortedAlphabet(d.Alphabet)
+	}
+	syms := []rune{}
+	for _, s := range a.AsNFA().States {
+		for sym := range s.Trans {
+			if sym != thompson.Epsilon && !config.ContainsRune(syms, sym) {
+				syms = append(syms, sym)
+			}
+		}
+	}
+	return sortedAlphabet(syms)
+}
+
+// domain retorna los símbolos de origen del homomorfismo, ordenados.
+func (h Homomorphism) domain() []rune {
+	syms := make([]rune, 0, len(h))
+	for sym := range h {
+		syms = append(syms, sym)
+	}
+	sort.Slice(syms, func(i, j int) bool { return syms[i] < syms[j] })
+	return syms
+}
+
+// ApplyHomomorphism construye un NFA para h(L(a)): cada transición p --x--> q se reemplaza por
+// una cadena de estados que deletrea h(x) (o por una transición ε si h(x) es vacía).
+// Retorna error si algún símbolo del autómata no tiene imagen.
+func ApplyHomomorphism(a Automaton, h Homomorphism) (*thompson.NFA, error) {
+	bld := &nfaBuilder{}
+	n, copies := bld.clone(a)
+	for _, s := range sortedStates(n) {
+		cp := copies[s]
+		orig := detachSymbolEdges(cp)
+		for _, sym := range sortedSymbols(orig) {
+			image, ok := h[sym]
+			if !ok {
+				return nil, fmt.Errorf("el homomorfismo no define una imagen para el símbolo %q", sym)
+			}
+			for _, t := range orig[sym] {
+				spellPath(bld, cp, image, t)
+			}
+		}
+	}
+	return bld.build(copies[n.Start], copies[n.Accept]), nil
+}
+
+// detachSymbolEdges quita del estado sus transiciones por símbolo (conserva las ε) y las retorna,
+// para poder reemplazarlas sin mezclar las transiciones nuevas con las originales.
+func detachSymbolEdges(s *thompson.State) map[rune][]*thompson.State {
+	orig := s.Trans
+	s.Trans = make(map[rune][]*thompson.State)
+	if eps, ok := orig[thompson.Epsilon]; ok {
+		s.Trans[thompson.Epsilon] = eps
+		delete(orig, thompson.Epsilon)
+	}
+	return orig
+}
+
+// spellPath conecta from con to mediante estados intermedios que deletrean w (ε si w es vacía).
+func spellPath(bld *nfaBuilder, from *thompson.State, w string, to *thompson.State) {
+	if w == "" {
+		from.AddEdge(thompson.Epsilon, to)
+		return
+	}
+	cur := from
+	for len(w) > 0 {
+		r, size := utf8.DecodeRuneInString(w)
+		w = w[size:]
+		next := to
+		if len(w) > 0 {
+			next = bld.newState()
+		}
+		cur.AddEdge(r, next)
+		cur = next
+	}
+}
+
+// InverseHomomorphism construye un DFA para h⁻¹(L(a)) = { w | h(w) ∈ L(a) } sobre el dominio de h.
+// Los estados son los del DFA total de a, y δ'(q, x) = δ̂(q, h(x)).
+func InverseHomomorphism(a Automaton, h Homomorphism) *DFA {
+	// Alfabeto de destino: el del autómata más los símbolos de las imágenes
+	target := Alphabet(a)
+	for _, image := range h {
+		for _, c := range image {
+			if !config.ContainsRune(target, c) {
+				target = append(target, c)
+			}
+		}
+	}
+	d := toDFA(a, target)
+
+	source := h.domain()
+	result := &DFA{
+		States:      append([]string{}, d.States...),
+		Alphabet:    source,
+		Transitions: make(map[string]map[rune]string, len(d.States)),
+		Start:       d.Start,
+		Accepting:   map[string]bool{},
+	}
+	for _, state := range d.States {
+		row := make(map[rune]string, len(source))
+		for _, sym := range source {
+			cur := state
+			for _, c := range h[sym] {
+				cur = d.Transitions[cur][c]
+			}
+			row[sym] = cur
+		}
+		result.Transitions[state] = row
+		if d.Accepting[state] {
+			result.Accepting[state] = true
+		}
+	}
+	return result
+}
+
+// Substitute construye un NFA para s(L(a)): cada transición p --x--> q se reemplaza por una copia
+// del autómata de s(x), conectada con p y q mediante transiciones ε.
+// Retorna error si algún símbolo del autómata no tiene un lenguaje asignado.
+func Substitute(a Automaton, sub Substitution) (*thompson.NFA, error) {
+	bld := &nfaBuilder{}
+	n, copies := bld.clone(a)
+	for _, s := range sortedStates(n) {
+		cp := copies[s]
+		orig := detachSymbolEdges(cp)
+		for _, sym := range sortedSymbols(orig) {
+			lang, ok := sub[sym]
+			if !ok {
+				return nil, fmt.Errorf("la sustitución no define un lenguaje para el símbolo %q", sym)
+			}
+			for _, t := range orig[sym] {
+				ln, lc := bld.clone(lang)
+				cp.AddEdge(thompson.Epsilon, lc[ln.Start])
+				lc[ln.Accept].AddEdge(thompson.Epsilon, t)
+			}
+		}
+	}
+	return bld.build(copies[n.Start], copies[n.Accept]), nil
+}
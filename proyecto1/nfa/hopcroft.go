@@ -0,0 +1,241 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la minimización de DFA con el algoritmo de Hopcroft.
+package nfa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MinimizeAlgorithm selecciona el algoritmo usado para minimizar un DFA.
+type MinimizeAlgorithm int
+
+const (
+	Hopcroft   MinimizeAlgorithm = iota // Refinamiento con lista de trabajo, O(n log n) (por defecto)
+	Moore                               // Refinamiento por firmas, algoritmo de referencia
+	Check                               // Ejecuta todos y verifica que los resultados sean isomorfos
+	Brzozowski                          // Doble reversión y determinización (ver reverse.go)
+)
+
+// String retorna el nombre del algoritmo tal como se acepta en la línea de comandos.
+func (a MinimizeAlgorithm) String() string {
+	switch a {
+	case Hopcroft:
+		return "hopcroft"
+	case Moore:
+		return "moore"
+	case Check:
+		return "check"
+	case Brzozowski:
+		return "brzozowski"
+	default:
+		return fmt.Sprintf("MinimizeAlgorithm(%d)", int(a))
+	}
+}
+
+// ParseMinimizeAlgorithm convierte un nombre ("hopcroft", "moore", "brzozowski", "check") en un MinimizeAlgorithm.
+func ParseMinimizeAlgorithm(name string) (MinimizeAlgorithm, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "hopcroft":
+		return Hopcroft, nil
+	case "moore":
+		return Moore, nil
+	case "brzozowski":
+		return Brzozowski, nil
+	case "check":
+		return Check, nil
+	default:
+		return Hopcroft, fmt.Errorf("algoritmo de minimización desconocido %q (use hopcroft, moore, brzozowski o check)", name)
+	}
+}
+
+// MinimizeDFAWith minimiza un DFA con el algoritmo indicado. El resultado siempre está en forma
+// canónica (ver Canonical), así que todos los algoritmos nombran igual a los estados.
+// En modo Check ejecuta Hopcroft, Moore y Brzozowski y retorna un error si los DFAs resultantes
+// no son isomorfos entre sí.
+func MinimizeDFAWith(dfa *DFA, alg MinimizeAlgorithm) (*DFA, error) {
+	switch alg {
+	case Hopcroft:
+		return MinimizeDFA(dfa), nil
+	case Moore:
+		return Canonical(minimizeMoore(Trim(dfa))), nil
+	case Brzozowski:
+		return Canonical(minimizeBrzozowski(dfa)), nil
+	case Check:
+		h := MinimizeDFA(dfa)
+		for _, other := range []MinimizeAlgorithm{Moore, Brzozowski} {
+			o, _ := MinimizeDFAWith(dfa, other)
+			if _, ok := Isomorphic(h, o); !ok {
+				return nil, fmt.Errorf("hopcroft (%d estados) y %v (%d estados) no producen DFAs isomorfos",
+					len(h.States), other, len(o.States))
+			}
+		}
+		return h, nil
+	default:
+		return nil, fmt.Errorf("algoritmo de minimización desconocido: %v", alg)
+	}
+}
+
+// minimizeHopcroft minimiza un DFA con el algoritmo de Hopcroft.
+// Las transiciones faltantes se dirigen a un estado sumidero virtual que empieza en su propio bloque,
+// de modo que la partición final coincide con la del refinamiento de Moore.
+func minimizeHopcroft(dfa *DFA) *DFA {
+	// Paso 1: Enumerar los estados alcanzables (en el orden de dfa.States)
+	reachable := getReachableStates(dfa)
+	names := []string{}
+	index := map[string]int{}
+	for _, state := range dfa.States {
+		if reachable[state] {
+			index[state] = len(names)
+			names = append(names, state)
+		}
+	}
+	n := len(names)
+	sink := n // Estado sumidero virtual
+	total := n + 1
+	k := len(dfa.Alphabet)
+
+	// Paso 2: Transiciones inversas: inv[c][j] = estados i tales que δ(i, c) = j
+	inv := make([][][]int, k)
+	for c, symbol := range dfa.Alphabet {
+		inv[c] = make([][]int, total)
+		for i, state := range names {
+			j := sink
+			if next, ok := dfa.Transitions[state][symbol]; ok {
+				if idx, ok := index[next]; ok {
+					j = idx
+				}
+			}
+			inv[c][j] = append(inv[c][j], i)
+		}
+		inv[c][sink] = append(inv[c][sink], sink)
+	}
+
+	// Paso 3: Partición inicial {aceptación, no aceptación, sumidero}.
+	// Los bloques son rangos [start, end) del arreglo elems; loc da la posición de cada estado.
+	elems := make([]int, 0, total)
+	blockOf := make([]int, total)
+	var start, end []int
+	addBlock := func(members []int) {
+		if len(members) == 0 {
+			return
+		}
+		b := len(start)
+		start = append(start, len(elems))
+		for _, s := range members {
+			blockOf[s] = b
+			elems = append(elems, s)
+		}
+		end = append(end, len(elems))
+	}
+	accepting, nonAccepting := []int{}, []int{}
+	for i, state := range names {
+		if dfa.Accepting[state] {
+			accepting = append(accepting, i)
+		} else {
+			nonAccepting = append(nonAccepting, i)
+		}
+	}
+	addBlock(accepting)
+	addBlock(nonAccepting)
+	addBlock([]int{sink})
+	loc := make([]int, total)
+	for pos, s := range elems {
+		loc[s] = pos
+	}
+
+	// Paso 4: Lista de trabajo de pares (bloque, símbolo); al inicio contiene todos los bloques
+	type splitter struct{ block, sym int }
+	work := []splitter{}
+	inWork := [][]bool{}
+	for b := range start {
+		inWork = append(inWork, make([]bool, k))
+		for c := 0; c < k; c++ {
+			work = append(work, splitter{b, c})
+			inWork[b][c] = true
+		}
+	}
+
+	mid := make([]int, len(start)) // Frontera entre marcados [start, mid) y no marcados
+	copy(mid, start)
+	members := []int{} // Copia de los estados del bloque divisor
+
+	for len(work) > 0 {
+		sp := work[len(work)-1]
+		work = work[:len(work)-1]
+		inWork[sp.block][sp.sym] = false
+
+		// Marca los predecesores del bloque divisor moviéndolos al frente de su bloque. Los miembros del
+		// divisor se copian antes: si el divisor es también un bloque de predecesores, marcar reordena su
+		// rango de elems y el recorrido visitaría algunos estados dos veces y otros ninguna.
+		members = append(members[:0], elems[start[sp.block]:end[sp.block]]...)
+		touched := []int{}
+		for _, s := range members {
+			for _, p := range inv[sp.sym][s] {
+				b := blockOf[p]
+				if loc[p] < mid[b] {
+					continue // Ya marcado
+				}
+				if mid[b] == start[b] {
+					touched = append(touched, b)
+				}
+				q := elems[mid[b]]
+				elems[loc[p]], elems[mid[b]] = q, p
+				loc[q], loc[p] = loc[p], mid[b]
+				mid[b]++
+			}
+		}
+
+		// Divide cada bloque tocado que no quedó completamente marcado
+		for _, b := range touched {
+			if mid[b] == end[b] {
+				mid[b] = start[b]
+				continue
+			}
+			nb := len(start)
+			start = append(start, start[b])
+			end = append(end, mid[b])
+			mid = append(mid, start[b])
+			inWork = append(inWork, make([]bool, k))
+			for pos := start[nb]; pos < end[nb]; pos++ {
+				blockOf[elems[pos]] = nb
+			}
+			start[b] = end[nb]
+			mid[b] = start[b]
+
+			// Agrega la mitad más pequeña (o ambas, si b ya estaba pendiente)
+			smaller := nb
+			if end[b]-start[b] < end[nb]-start[nb] {
+				smaller = b
+			}
+			for c := 0; c < k; c++ {
+				target := smaller
+				if inWork[b][c] {
+					target = nb
+				}
+				if !inWork[target][c] {
+					inWork[target][c] = true
+					work = append(work, splitter{target, c})
+				}
+			}
+		}
+	}
+
+	// Paso 5: Convertir los bloques en particiones (sin el sumidero), ordenadas por su primer estado
+	partitions := []map[string]bool{}
+	seen := make([]bool, len(start))
+	for i := 0; i < n; i++ {
+		b := blockOf[i]
+		if seen[b] || b == blockOf[sink] {
+			continue
+		}
+		seen[b] = true
+		partition := make(map[string]bool, end[b]-start[b])
+		for pos := start[b]; pos < end[b]; pos++ {
+			partition[names[elems[pos]]] = true
+		}
+		partitions = append(partitions, partition)
+	}
+
+	return buildMinimizedDFA(dfa, partitions)
+}
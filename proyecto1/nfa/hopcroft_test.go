@@ -0,0 +1,95 @@
+package nfa
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// randomDFA genera un DFA de n estados sobre alphabet; cada transición existe con probabilidad density
+// (las faltantes van al sumidero implícito) y cada estado acepta con probabilidad 1/3.
+func randomDFA(rng *rand.Rand, n int, alphabet []rune, density float64) *DFA {
+	dfa := &DFA{Alphabet: alphabet, Transitions: map[string]map[rune]string{}, Accepting: map[string]bool{}}
+	for i := 0; i < n; i++ {
+		dfa.States = append(dfa.States, fmt.Sprintf("s%d", i))
+	}
+	dfa.Start = dfa.States[0]
+	for _, s := range dfa.States {
+		dfa.Transitions[s] = map[rune]string{}
+		for _, sym := range alphabet {
+			if rng.Float64() < density {
+				dfa.Transitions[s][sym] = dfa.States[rng.Intn(n)]
+			}
+		}
+		if rng.Intn(3) == 0 {
+			dfa.Accepting[s] = true
+		}
+	}
+	return dfa
+}
+
+func TestHopcroftMatchesMoore(t *testing.T) {
+	tests := []struct {
+		name string
+		dfa  *DFA
+		want int
+	}{
+		{
+			// El divisor {s0, s2} es también el bloque de sus predecesores
+			name: "ciclo sin aceptación",
+			dfa: &DFA{
+				States:      []string{"s0", "s1", "s2"},
+				Alphabet:    []rune{'a'},
+				Transitions: map[string]map[rune]string{"s0": {'a': "s2"}, "s2": {'a': "s0"}},
+				Start:       "s0",
+				Accepting:   map[string]bool{},
+			},
+			want: 1,
+		},
+		{
+			name: "ab* parcial",
+			dfa: &DFA{
+				States:      []string{"s0", "s1", "s2"},
+				Alphabet:    []rune{'a', 'b'},
+				Transitions: map[string]map[rune]string{"s0": {'a': "s1"}, "s1": {'b': "s2"}, "s2": {'b': "s1"}},
+				Start:       "s0",
+				Accepting:   map[string]bool{"s1": true, "s2": true},
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := MinimizeDFA(tt.dfa)
+			m, _ := MinimizeDFAWith(tt.dfa, Moore)
+			if len(h.States) != tt.want {
+				t.Errorf("Hopcroft: %d estados, se esperaban %d", len(h.States), tt.want)
+			}
+			if _, ok := Isomorphic(h, m); !ok {
+				t.Errorf("Hopcroft (%d estados) y Moore (%d estados) no son isomorfos", len(h.States), len(m.States))
+			}
+		})
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		dfa := randomDFA(rng, 1+rng.Intn(12), []rune("abc")[:1+rng.Intn(3)], []float64{0.5, 0.8, 1}[rng.Intn(3)])
+		// Sin Trim: los estados muertos ponen a prueba el marcado con divisores que son su propio predecesor
+		h := Canonical(minimizeHopcroft(dfa))
+		m := Canonical(minimizeMoore(dfa))
+		if _, ok := Isomorphic(h, m); !ok {
+			t.Fatalf("DFA aleatorio %d: Hopcroft (%d estados) y Moore (%d estados) no son isomorfos\n%+v",
+				i, len(h.States), len(m.States), dfa)
+		}
+	}
+}
+
+func TestMinimizeDFAWithCheck(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 300; i++ {
+		dfa := randomDFA(rng, 1+rng.Intn(10), []rune("ab"), []float64{0.5, 1}[i%2])
+		if _, err := MinimizeDFAWith(dfa, Check); err != nil {
+			t.Fatalf("DFA aleatorio %d: %v", i, err)
+		}
+	}
+}
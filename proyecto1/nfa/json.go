@@ -0,0 +1,319 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo su serialización a JSON (ver docs/automaton.schema.json).
+package nfa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"proyecto1/config"
+	"proyecto1/thompson"
+	"unicode/utf8"
+)
+
+// Tipos de autómata en el campo "type" del JSON.
+const (
+	JSONTypeNFA = "nfa"
+	JSONTypeDFA = "dfa"
+)
+
+// JSONAutomaton es la representación JSON de un NFA o un DFA.
+// Los símbolos son cadenas de un solo carácter; "ε" (o "") denota una transición epsilon (solo NFA).
+type JSONAutomaton struct {
+	Type        string           `json:"type"`        // "nfa" o "dfa"
+	Alphabet    []string         `json:"alphabet"`    // Símbolos del alfabeto (sin ε)
+	States      []string         `json:"states"`      // Nombres de los estados
+	Start       string           `json:"start"`       // Estado inicial
+	Accepting   []string         `json:"accepting"`   // Estados de aceptación
+	Transitions []JSONTransition `json:"transitions"` // Transiciones
+}
+
+// JSONTransition es una transición from --symbol--> to.
+type JSONTransition struct {
+	From   string `json:"from"`
+	Symbol string `json:"symbol"`
+	To     string `json:"to"`
+}
+
+// nfaStateName nombra un estado del NFA en JSON (igual que en los archivos DOT).
+func nfaStateName(s *thompson.State) string {
+	return fmt.Sprintf("q%d", s.ID)
+}
+
+// NFAToJSON convierte un NFA a su representación JSON (estados ordenados por ID).
+func NFAToJSON(n *thompson.NFA) *JSONAutomaton {
+	j := &JSONAutomaton{
+		Type:        JSONTypeNFA,
+		Alphabet:    []string{},
+		States:      []string{},
+		Start:       nfaStateName(n.Start),
+		Accepting:   []string{nfaStateName(n.Accept)},
+		Transitions: []JSONTransition{},
+	}
+	for _, sym := range Alphabet(n) {
+		j.Alphabet = append(j.Alphabet, string(sym))
+	}
+	for _, s := range sortedStates(n) {
+		j.States = append(j.States, nfaStateName(s))
+		for _, sym := range sortedSymbols(s.Trans) {
+			for _, t := range s.Trans[sym] {
+				j.Transitions = append(j.Transitions, JSONTransition{
+					From:   nfaStateName(s),
+					Symbol: string(sym),
+					To:     nfaStateName(t),
+				})
+			}
+		}
+	}
+	return j
+}
+
+// DFAToJSON convierte un DFA a su representación JSON (en el orden de dfa.States y dfa.Alphabet).
+func DFAToJSON(dfa *DFA) *JSONAutomaton {
+	j := &JSONAutomaton{
+		Type:        JSONTypeDFA,
+		Alphabet:    []string{},
+		States:      append([]string{}, dfa.States...),
+		Start:       dfa.Start,
+		Accepting:   []string{},
+		Transitions: []JSONTransition{},
+	}
+	for _, sym := range dfa.Alphabet {
+		j.Alphabet = append(j.Alphabet, string(sym))
+	}
+	for _, state := range dfa.States {
+		if dfa.Accepting[state] {
+			j.Accepting = append(j.Accepting, state)
+		}
+		for _, sym := range dfa.Alphabet {
+			if to, ok := dfa.Transitions[state][sym]; ok {
+				j.Transitions = append(j.Transitions, JSONTransition{From: state, Symbol: string(sym), To: to})
+			}
+		}
+	}
+	return j
+}
+
+// EncodeNFA escribe el NFA en formato JSON (con sangría).
+func EncodeNFA(w io.Writer, n *thompson.NFA) error {
+	return encodeJSON(w, NFAToJSON(n))
+}
+
+// EncodeDFA escribe el DFA en formato JSON (con sangría).
+func EncodeDFA(w io.Writer, dfa *DFA) error {
+	return encodeJSON(w, DFAToJSON(dfa))
+}
+
+// encodeJSON escribe cualquier valor como JSON con sangría de dos espacios.
+func encodeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// DecodeAutomaton lee un autómata en formato JSON y retorna un *thompson.NFA o un *DFA según su tipo.
+func DecodeAutomaton(r io.Reader) (Automaton, error) {
+	var j JSONAutomaton
+	if err := json.NewDecoder(r).Decode(&j); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %v", err)
+	}
+	switch j.Type {
+	case JSONTypeNFA:
+		return j.ToNFA()
+	case JSONTypeDFA:
+		return j.ToDFA()
+	default:
+		return nil, fmt.Errorf("tipo de autómata desconocido %q (se esperaba %q o %q)", j.Type, JSONTypeNFA, JSONTypeDFA)
+	}
+}
+
+// DecodeNFA lee un NFA en formato JSON. Un DFA también se acepta y se convierte con FromDFA.
+func DecodeNFA(r io.Reader) (*thompson.NFA, error) {
+	a, err := DecodeAutomaton(r)
+	if err != nil {
+		return nil, err
+	}
+	return a.AsNFA(), nil
+}
+
+// DecodeDFA lee un DFA en formato JSON. Un NFA también se acepta y se determiniza sobre su alfabeto.
+func DecodeDFA(r io.Reader) (*DFA, error) {
+	a, err := DecodeAutomaton(r)
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := a.(*DFA); ok {
+		return d, nil
+	}
+	return NFAtoDFA(a.AsNFA(), Alphabet(a)), nil
+}
+
+// parseSymbol convierte un símbolo JSON en rune; "" y "ε" son epsilon.
+func parseSymbol(sym string) (rune, error) {
+	if sym == "" || sym == "ε" || sym == "𝜀" {
+		return thompson.Epsilon, nil
+	}
+	r, size := utf8.DecodeRuneInString(sym)
+	if size != len(sym) {
+		return 0, fmt.Errorf("el símbolo %q debe ser un único carácter", sym)
+	}
+	return r, nil
+}
+
+// parseAlphabet convierte el alfabeto JSON en runes; ε no puede formar parte del alfabeto.
+func (j *JSONAutomaton) parseAlphabet() ([]rune, error) {
+	alphabet := []rune{}
+	for _, a := range j.Alphabet {
+		sym, err := parseSymbol(a)
+		if err != nil {
+			return nil, err
+		}
+		if sym == thompson.Epsilon {
+			return nil, fmt.Errorf("ε no puede ser un símbolo del alfabeto")
+		}
+		if !config.ContainsRune(alphabet, sym) {
+			alphabet = append(alphabet, sym)
+		}
+	}
+	return alphabet, nil
+}
+
+// validateStates verifica que los estados no se repitan y que start y accepting existan.
+func (j *JSONAutomaton) validateStates() (map[string]int, error) {
+	if len(j.States) == 0 {
+		return nil, fmt.Errorf("el autómata no tiene estados")
+	}
+	index := make(map[string]int, len(j.States))
+	for i, s := range j.States {
+		if _, dup := index[s]; dup {
+			return nil, fmt.Errorf("estado repetido %q", s)
+		}
+		index[s] = i
+	}
+	if _, ok := index[j.Start]; !ok {
+		return nil, fmt.Errorf("el estado inicial %q no está en states", j.Start)
+	}
+	for _, s := range j.Accepting {
+		if _, ok := index[s]; !ok {
+			return nil, fmt.Errorf("el estado de aceptación %q no está en states", s)
+		}
+	}
+	return index, nil
+}
+
+// ToNFA construye un NFA a partir de la representación JSON. Los estados reciben IDs según su
+// posición en states; si hay varios estados de aceptación (o ninguno) se agrega uno nuevo,
+// alcanzable con ε desde cada estado de aceptación.
+func (j *JSONAutomaton) ToNFA() (*thompson.NFA, error) {
+	index, err := j.validateStates()
+	if err != nil {
+		return nil, err
+	}
+	alphabet, err := j.parseAlphabet()
+	if err != nil {
+		return nil, err
+	}
+
+	// IDs: los de los nombres "q<n>" (como los genera NFAToJSON) o, si no, la posición en states
+	ids := make([]int, len(j.States))
+	usedIDs := map[int]bool{}
+	for i, name := range j.States {
+		var id int
+		if _, err := fmt.Sscanf(name, "q%d", &id); err != nil || fmt.Sprintf("q%d", id) != name || usedIDs[id] {
+			for k := range ids {
+				ids[k] = k
+			}
+			break
+		}
+		usedIDs[id] = true
+		ids[i] = id
+	}
+	nextID := 0
+	states := make([]*thompson.State, 0, len(j.States)+1)
+	for _, id := range ids {
+		states = append(states, thompson.NewState(id))
+		if id >= nextID {
+			nextID = id + 1
+		}
+	}
+	for _, t := range j.Transitions {
+		from, okFrom := index[t.From]
+		to, okTo := index[t.To]
+		if !okFrom || !okTo {
+			return nil, fmt.Errorf("transición %s --%s--> %s usa un estado inexistente", t.From, t.Symbol, t.To)
+		}
+		sym, err := parseSymbol(t.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if sym != thompson.Epsilon && len(alphabet) > 0 && !config.ContainsRune(alphabet, sym) {
+			return nil, fmt.Errorf("el símbolo %q de la transición desde %s no está en el alfabeto", sym, t.From)
+		}
+		states[from].AddEdge(sym, states[to])
+	}
+
+	var accept *thompson.State
+	if len(j.Accepting) == 1 {
+		accept = states[index[j.Accepting[0]]]
+	} else {
+		accept = thompson.NewState(nextID)
+		for _, s := range j.Accepting {
+			states[index[s]].AddEdge(thompson.Epsilon, accept)
+		}
+		states = append(states, accept)
+	}
+
+	return &thompson.NFA{
+		Start:  states[index[j.Start]],
+		Accept: accept,
+		States: states,
+	}, nil
+}
+
+// ToDFA construye un DFA a partir de la representación JSON.
+// Retorna error si hay transiciones ε, símbolos fuera del alfabeto o más de un destino por símbolo.
+func (j *JSONAutomaton) ToDFA() (*DFA, error) {
+	if _, err := j.validateStates(); err != nil {
+		return nil, err
+	}
+	dfa := &DFA{
+		States:      append([]string{}, j.States...),
+		Alphabet:    []rune{},
+		Transitions: make(map[string]map[rune]string, len(j.States)),
+		Start:       j.Start,
+		Accepting:   map[string]bool{},
+	}
+	alphabet, err := j.parseAlphabet()
+	if err != nil {
+		return nil, err
+	}
+	dfa.Alphabet = alphabet
+	for _, s := range j.States {
+		dfa.Transitions[s] = map[rune]string{}
+	}
+	for _, s := range j.Accepting {
+		dfa.Accepting[s] = true
+	}
+	for _, t := range j.Transitions {
+		row, okFrom := dfa.Transitions[t.From]
+		_, okTo := dfa.Transitions[t.To]
+		if !okFrom || !okTo {
+			return nil, fmt.Errorf("transición %s --%s--> %s usa un estado inexistente", t.From, t.Symbol, t.To)
+		}
+		sym, err := parseSymbol(t.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if sym == thompson.Epsilon {
+			return nil, fmt.Errorf("el DFA no puede tener transiciones ε (desde %s)", t.From)
+		}
+		if !config.ContainsRune(dfa.Alphabet, sym) {
+			return nil, fmt.Errorf("el símbolo %q de la transición desde %s no está en el alfabeto", sym, t.From)
+		}
+		if prev, dup := row[sym]; dup && prev != t.To {
+			return nil, fmt.Errorf("el estado %s tiene más de una transición con %q", t.From, sym)
+		}
+		row[sym] = t.To
+	}
+	return dfa, nil
+}
@@ -0,0 +1,116 @@
+package nfa
+
+import (
+	"bytes"
+	"testing"
+
+	"proyecto1/config"
+	"proyecto1/regex"
+	"proyecto1/thompson"
+)
+
+// buildNFA construye el NFA de Thompson de la regex igual que el pipeline.
+func buildNFA(t *testing.T, r string) *thompson.NFA {
+	t.Helper()
+	postfix := config.InfixToPostfix(config.FormatRegex(config.ExpandRegexExtensions(r)))
+	ast, err := regex.BuildAST(postfix)
+	if err != nil {
+		t.Fatalf("%s: %v", r, err)
+	}
+	n, err := thompson.Build(ast)
+	if err != nil {
+		t.Fatalf("%s: %v", r, err)
+	}
+	return n
+}
+
+// allWords retorna todas las cadenas sobre alphabet de longitud ≤ k.
+func allWords(alphabet []rune, k int) []string {
+	words := []string{""}
+	level := []string{""}
+	for n := 0; n < k; n++ {
+		next := []string{}
+		for _, w := range level {
+			for _, sym := range alphabet {
+				next = append(next, w+string(sym))
+			}
+		}
+		words = append(words, next...)
+		level = next
+	}
+	return words
+}
+
+var jsonRegexes = []string{
+	"a(b|c)*",
+	"(a|ε)b*",
+	"ñ(λ|ü)*ñ", // Símbolos fuera de ASCII
+	"(a|b)*abb",
+	"ε",
+}
+
+func TestNFAJSONRoundTrip(t *testing.T) {
+	for _, r := range jsonRegexes {
+		t.Run(r, func(t *testing.T) {
+			n := buildNFA(t, r)
+			var first, second bytes.Buffer
+			if err := EncodeNFA(&first, n); err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := DecodeNFA(bytes.NewReader(first.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := EncodeNFA(&second, decoded); err != nil {
+				t.Fatal(err)
+			}
+			if first.String() != second.String() {
+				t.Errorf("el JSON cambia en la ida y vuelta:\n%s\n%s", first.String(), second.String())
+			}
+
+			alphabet := Alphabet(n)
+			if _, ok := Isomorphic(MinimizeDFA(NFAtoDFA(n, alphabet)), MinimizeDFA(NFAtoDFA(decoded, alphabet))); !ok {
+				t.Errorf("los DFAs mínimos del NFA original y el decodificado no son isomorfos")
+			}
+			for _, w := range allWords(alphabet, 4) {
+				if Simulate(n, w) != Simulate(decoded, w) {
+					t.Errorf("%q: el NFA original y el decodificado no coinciden", w)
+				}
+			}
+		})
+	}
+}
+
+func TestDFAJSONRoundTrip(t *testing.T) {
+	for _, r := range jsonRegexes {
+		n := buildNFA(t, r)
+		alphabet := Alphabet(n)
+		dfa := NFAtoDFA(n, alphabet)
+		for name, d := range map[string]*DFA{"dfa": dfa, "min": MinimizeDFA(dfa)} {
+			t.Run(r+"/"+name, func(t *testing.T) {
+				var first, second bytes.Buffer
+				if err := EncodeDFA(&first, d); err != nil {
+					t.Fatal(err)
+				}
+				decoded, err := DecodeDFA(bytes.NewReader(first.Bytes()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := EncodeDFA(&second, decoded); err != nil {
+					t.Fatal(err)
+				}
+				if first.String() != second.String() {
+					t.Errorf("el JSON cambia en la ida y vuelta:\n%s\n%s", first.String(), second.String())
+				}
+				if _, ok := Isomorphic(d, decoded); !ok {
+					t.Errorf("el DFA decodificado no es isomorfo al original")
+				}
+				for _, w := range allWords(alphabet, 4) {
+					if SimulateDFA(d, w) != SimulateDFA(decoded, w) || SimulateDFA(d, w) != Simulate(n, w) {
+						t.Errorf("%q: los veredictos no coinciden", w)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo un DFA perezoso que construye los subconjuntos a medida que la entrada los pide.
+package nfa
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"proyecto1/thompson"
+)
+
+// DefaultLazyMemory es el límite de memoria por defecto de la caché de un LazyDFA (8 MiB).
+const DefaultLazyMemory = 8 << 20
+
+// Costos aproximados, en bytes, con los que un LazyDFA mide su caché.
+const (
+	lazyStateCost      = 128 // Estado cacheado (estructura, clave y mapa de transiciones vacío)
+	lazyMemberCost     = 16  // Cada estado del NFA en el conjunto
+	lazyTransitionCost = 32  // Cada transición cacheada
+)
+
+// lazyMaxFlushes es la cantidad de veces que se puede vaciar la caché en una misma entrada antes de
+// abandonar el DFA y seguir simulando el NFA: si la caché no alcanza ni para una entrada, reconstruir
+// estados que se descartan enseguida es más caro que la simulación directa.
+const lazyMaxFlushes = 3
+
+// lazyState es un estado del DFA perezoso: un conjunto de estados del NFA (cerrado por ε) con las
+// transiciones que ya se calcularon.
+type lazyState struct {
+	set       stateSet
+	accepting bool
+	next      map[rune]*lazyState
+}
+
+// LazyStats son contadores del uso de la caché de un LazyDFA.
+type LazyStats struct {
+	States    int `json:"states"`    // Estados en la caché
+	Memory    int `json:"memory"`    // Bytes estimados de la caché
+	Hits      int `json:"hits"`      // Transiciones resueltas por la caché
+	Misses    int `json:"misses"`    // Transiciones calculadas con el NFA
+	Flushes   int `json:"flushes"`   // Veces que se vació la caché por falta de memoria
+	Fallbacks int `json:"fallbacks"` // Entradas que terminaron con simulación del NFA
+}
+
+// LazyDFA es un DFA construido bajo demanda a partir de un NFA: cada estado del algoritmo de
+// subconjuntos se calcula la primera vez que la entrada llega a él y se guarda en una caché, de modo
+// que solo existen los estados que se visitan. Sirve para patrones como (a|b)*a(a|b){15}, cuyo DFA
+// completo tiene un número exponencial de estados.
+//
+// Cuando la caché supera el límite de memoria se vacía y se sigue desde el estado actual. Si en una
+// misma entrada se vacía más de lazyMaxFlushes veces, o si ni vaciándola cabe el paso siguiente, el resto
+// de la entrada se simula con el NFA; así la memoria estimada de la caché nunca supera el límite.
+// LazyDFA implementa Runner.
+type LazyDFA struct {
+	nfa   *thompson.NFA
+	limit int
+	used  int
+	cache map[string]*lazyState
+
+	startSet   stateSet
+	start, cur *lazyState
+	fallback   *NFARunner // No nil: se está simulando el NFA hasta el próximo Reset
+	runFlushes int        // Vaciados de la caché desde el último Reset
+	stats      LazyStats
+}
+
+// NewLazyDFA crea un DFA perezoso para el NFA, con una caché de a lo sumo memLimit bytes
+// (aproximados; DefaultLazyMemory si memLimit <= 0). Retorna un error si el límite no alcanza ni para
+// el estado inicial, que la caché conserva siempre.
+func NewLazyDFA(n *thompson.NFA, memLimit int) (*LazyDFA, error) {
+	if memLimit <= 0 {
+		memLimit = DefaultLazyMemory
+	}
+	l := &LazyDFA{nfa: n, limit: memLimit, cache: map[string]*lazyState{}}
+	l.startSet = make(stateSet)
+	add(l.startSet, n.Start)
+	l.startSet = epsilonClosure(l.startSet)
+	if cost := stateCost(l.startSet); cost > memLimit {
+		return nil, fmt.Errorf("el límite de memoria (%d bytes) no alcanza para el estado inicial del DFA perezoso (%d bytes)", memLimit, cost)
+	}
+	l.start = l.intern(l.startSet)
+	l.Reset()
+	return l, nil
+}
+
+// stateCost es la memoria estimada de cachear el estado del conjunto set.
+func stateCost(set stateSet) int { return lazyStateCost + lazyMemberCost*len(set) }
+
+// setKey identifica un conjunto de estados del NFA por sus IDs ordenados.
+func setKey(set stateSet) string {
+	ids := make([]int, 0, len(set))
+	for s := range set {
+		ids = append(ids, s.ID)
+	}
+	sort.Ints(ids)
+	key := make([]byte, 0, 4*len(ids))
+	for _, id := range ids {
+		key = strconv.AppendInt(key, int64(id), 10)
+		key = append(key, ',')
+	}
+	return string(key)
+}
+
+// intern retorna el estado cacheado para el conjunto, creándolo si no existe.
+func (l *LazyDFA) intern(set stateSet) *lazyState {
+	key := setKey(set)
+	if st, ok := l.cache[key]; ok {
+		return st
+	}
+	_, accepting := set[l.nfa.Accept]
+	st := &lazyState{set: set, accepting: accepting, next: map[rune]*lazyState{}}
+	l.cache[key] = st
+	l.used += stateCost(set)
+	return st
+}
+
+// flush vacía la caché y vuelve a agregar el estado inicial. El estado actual queda fuera de la caché
+// hasta que Step lo vuelva a agregar.
+func (l *LazyDFA) flush() {
+	l.stats.Flushes++
+	l.runFlushes++
+	l.cache = map[string]*lazyState{}
+	l.used = 0
+	l.start = l.intern(l.startSet)
+}
+
+// stepCost es la memoria estimada de cachear la transición de l.cur a set, incluidos los estados que
+// falten en la caché (l.cur puede faltar después de vaciarla).
+func (l *LazyDFA) stepCost(set stateSet) int {
+	cost := lazyTransitionCost
+	curKey, key := setKey(l.cur.set), setKey(set)
+	if _, ok := l.cache[curKey]; !ok {
+		cost += stateCost(l.cur.set)
+	}
+	if _, ok := l.cache[key]; !ok && key != curKey {
+		cost += stateCost(set)
+	}
+	return cost
+}
+
+// Reset vuelve al estado inicial y, si se había abandonado el DFA, vuelve a usarlo.
+func (l *LazyDFA) Reset() {
+	l.cur = l.start
+	l.fallback = nil
+	l.runFlushes = 0
+}
+
+// Step consume un símbolo: usa la transición cacheada o la calcula con el NFA.
+func (l *LazyDFA) Step(sym rune) {
+	if l.fallback != nil {
+		l.fallback.Step(sym)
+		return
+	}
+	if next, ok := l.cur.next[sym]; ok {
+		l.stats.Hits++
+		l.cur = next
+		return
+	}
+	l.stats.Misses++
+	set := epsilonClosure(move(l.cur.set, sym))
+	if l.used+l.stepCost(set) > l.limit && l.runFlushes < lazyMaxFlushes {
+		l.flush()
+	}
+	if l.used+l.stepCost(set) > l.limit {
+		// Sin vaciados disponibles, o el paso no cabe ni con la caché vacía
+		l.stats.Fallbacks++
+		l.fallback = &NFARunner{nfa: l.nfa, current: set}
+		return
+	}
+	l.cur = l.intern(l.cur.set)
+	next := l.intern(set)
+	l.cur.next[sym] = next
+	l.used += lazyTransitionCost
+	l.cur = next
+}
+
+// Accepting indica si la entrada consumida es aceptada.
+func (l *LazyDFA) Accepting() bool {
+	if l.fallback != nil {
+		return l.fallback.Accepting()
+	}
+	return l.cur.accepting
+}
+
+// Dead indica si no quedan estados activos del NFA.
+func (l *LazyDFA) Dead() bool {
+	if l.fallback != nil {
+		return l.fallback.Dead()
+	}
+	return len(l.cur.set) == 0
+}
+
+// Stats retorna los contadores de uso de la caché.
+func (l *LazyDFA) Stats() LazyStats {
+	s := l.stats
+	s.States = len(l.cache)
+	s.Memory = l.used
+	return s
+}
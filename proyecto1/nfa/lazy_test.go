@@ -0,0 +1,47 @@
+package nfa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLazyDFAMatchesNFA(t *testing.T) {
+	tests := []struct {
+		regex   string
+		limit   int  // Límite de la caché en bytes
+		flushes bool // Se espera que la caché se vacíe
+	}{
+		{"(a|b)*abb", DefaultLazyMemory, false},
+		{"(a|b)*a(a|b)(a|b)(a|b)", DefaultLazyMemory, false},
+		{"(a|b)*a(a|b)(a|b)(a|b)", 1200, true}, // Vacía la caché y abandona el DFA
+		{"(a|b)*a" + strings.Repeat("(a|b)", 8), 2000, true},
+		{"(ab|ba)*(a|ε)", 700, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			n := buildNFA(t, tt.regex)
+			l, err := NewLazyDFA(n, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range allWords(Alphabet(n), 10) {
+				if got, want := Run(l, w), Simulate(n, w); got != want {
+					t.Errorf("%q: LazyDFA %v, Simulate %v", w, got, want)
+				}
+				if s := l.Stats(); s.Memory > tt.limit {
+					t.Fatalf("%q: la caché usa %d bytes, más que el límite de %d", w, s.Memory, tt.limit)
+				}
+			}
+			if s := l.Stats(); (s.Flushes > 0) != tt.flushes {
+				t.Errorf("%d vaciados de la caché, se esperaban vaciados: %v", s.Flushes, tt.flushes)
+			}
+		})
+	}
+}
+
+func TestNewLazyDFARejectsSmallLimit(t *testing.T) {
+	n := buildNFA(t, "(a|b)*abb")
+	if _, err := NewLazyDFA(n, lazyStateCost); err == nil {
+		t.Errorf("NewLazyDFA aceptó un límite menor que el costo del estado inicial")
+	}
+}
@@ -6,13 +6,24 @@ import (
 	"fmt"
 )
 
-// MinimizeDFA minimiza un DFA usando el algoritmo de partición de estados.
+// MinimizeDFA minimiza un DFA usando el algoritmo de Hopcroft (ver hopcroft.go) y retorna
+// el resultado en forma canónica. Para elegir otro algoritmo se usa MinimizeDFAWith.
+// Los estados muertos se eliminan antes (ver Trim), así que el resultado puede ser parcial,
+// como el de Brzozowski.
+func MinimizeDFA(dfa *DFA) *DFA {
+	return Canonical(minimizeHopcroft(Trim(dfa)))
+}
+
+// minimizeMoore minimiza un DFA usando el algoritmo de partición de estados (refinamiento de Moore).
+// Se conserva como algoritmo de referencia para comparar con Hopcroft. Como en Hopcroft, una transición
+// faltante distingue al estado de los que tienen la transición, así que el resultado es mínimo solo si
+// el DFA no tiene estados muertos: MinimizeDFAWith aplica antes Trim.
 // El proceso consiste en:
 // 1. Eliminar estados inalcanzables.
 // 2. Crear particiones iniciales (estados de aceptación vs. no aceptación).
 // 3. Refinar las particiones hasta que no se puedan dividir más.
 // 4. Construir un nuevo DFA donde cada partición es un estado.
-func MinimizeDFA(dfa *DFA) *DFA {
+func minimizeMoore(dfa *DFA) *DFA {
 	// Paso 1: Eliminar estados inalcanzables
 	reachable := getReachableStates(dfa)
 
@@ -182,21 +193,25 @@ func buildMinimizedDFA(dfa *DFA, partitions []map[string]bool) *DFA {
 	// Mapea cada estado original a su representante de partición
 	stateToRep := make(map[string]string)
 	partitionReps := make([]string, len(partitions))
+	repIndex := make(map[string]int, len(partitions))
 
+	partitionOf := make(map[string]int, len(dfa.States))
 	for i, partition := range partitions {
-		// Elige un representante para la partición (el primero que encuentre)
-		var rep string
 		for state := range partition {
-			rep = state
-			break
+			partitionOf[state] = i
 		}
-		partitionReps[i] = rep
-
-		// Asigna todos los estados de la partición a ese representante
-		for state := range partition {
-			stateToRep[state] = rep
+	}
+	// El representante de cada partición es su primer estado según el orden de dfa.States,
+	// para no depender del orden de iteración del mapa; basta una pasada sobre los estados
+	for _, state := range dfa.States {
+		if i, ok := partitionOf[state]; ok && partitionReps[i] == "" {
+			partitionReps[i] = state
+			repIndex[state] = i
 		}
 	}
+	for state, i := range partitionOf {
+		stateToRep[state] = partitionReps[i]
+	}
 
 	// Crea los estados del nuevo DFA
 	newStates := []string{}
@@ -213,8 +228,9 @@ func buildMinimizedDFA(dfa *DFA, partitions []map[string]bool) *DFA {
 			newAccepting[newName] = true
 		}
 
-		// Si el representante es el estado inicial, el nuevo estado también lo es
-		if rep == dfa.Start {
+		// Si la partición contiene el estado inicial, el nuevo estado es el inicial
+		// (el representante no siempre es el estado inicial original)
+		if rep == stateToRep[dfa.Start] {
 			newStart = newName
 		}
 	}
@@ -234,12 +250,8 @@ func buildMinimizedDFA(dfa *DFA, partitions []map[string]bool) *DFA {
 		for _, symbol := range dfa.Alphabet {
 			if nextState, exists := dfa.Transitions[rep][symbol]; exists {
 				// Busca el nuevo nombre para el estado destino
-				nextRep := stateToRep[nextState]
-				for j, otherRep := range partitionReps {
-					if otherRep == nextRep {
-						newTransitions[newName][symbol] = fmt.Sprintf("q%d", j)
-						break
-					}
+				if nextRep, ok := stateToRep[nextState]; ok {
+					newTransitions[newName][symbol] = fmt.Sprintf("q%d", repIndex[nextRep])
 				}
 			}
 		}
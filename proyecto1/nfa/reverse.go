@@ -0,0 +1,199 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la reversión de autómatas y la minimización de Brzozowski.
+package nfa
+
+import (
+	"fmt"
+	"proyecto1/thompson"
+	"sort"
+)
+
+// Reverse construye un NFA que reconoce el reverso del lenguaje del autómata.
+// Cada transición p --a--> q se convierte en q --a--> p, el estado de aceptación pasa a ser
+// el inicial y viceversa. Los estados conservan sus IDs.
+func Reverse(a Automaton) *thompson.NFA {
+	n := a.AsNFA()
+	states := sortedStates(n)
+	copies := make(map[*thompson.State]*thompson.State, len(states))
+	for _, s := range states {
+		copies[s] = thompson.NewState(s.ID)
+	}
+
+	// Invierte cada transición (en orden de ID y símbolo para que el resultado sea estable)
+	for _, s := range states {
+		for _, sym := range sortedSymbols(s.Trans) {
+			for _, t := range s.Trans[sym] {
+				copies[t].AddEdge(sym, copies[s])
+			}
+		}
+	}
+
+	reversed := make([]*thompson.State, 0, len(states))
+	for _, s := range states {
+		reversed = append(reversed, copies[s])
+	}
+	return &thompson.NFA{
+		Start:  copies[n.Accept],
+		Accept: copies[n.Start],
+		States: reversed,
+	}
+}
+
+// AsNFA convierte el DFA en un NFA equivalente (ver FromDFA), de modo que *DFA implementa Automaton.
+func (dfa *DFA) AsNFA() *thompson.NFA {
+	return FromDFA(dfa)
+}
+
+// FromDFA convierte un DFA en un NFA con un único estado de aceptación.
+// Cada estado del DFA se vuelve un estado del NFA (IDs según el orden de dfa.States) y se agrega
+// un estado de aceptación nuevo, alcanzable con ε desde cada estado de aceptación del DFA.
+func FromDFA(dfa *DFA) *thompson.NFA {
+	states := make([]*thompson.State, 0, len(dfa.States)+1)
+	byName := make(map[string]*thompson.State, len(dfa.States))
+	for i, name := range dfa.States {
+		s := thompson.NewState(i)
+		byName[name] = s
+		states = append(states, s)
+	}
+	accept := thompson.NewState(len(dfa.States))
+	states = append(states, accept)
+
+	for _, name := range dfa.States {
+		from := byName[name]
+		for _, sym := range dfa.Alphabet {
+			if to, ok := dfa.Transitions[name][sym]; ok {
+				from.AddEdge(sym, byName[to])
+			}
+		}
+		if dfa.Accepting[name] {
+			from.AddEdge(thompson.Epsilon, accept)
+		}
+	}
+
+	return &thompson.NFA{
+		Start:  byName[dfa.Start],
+		Accept: accept,
+		States: states,
+	}
+}
+
+// MinimizeBrzozowski construye el DFA mínimo de un NFA con el algoritmo de Brzozowski:
+// revertir → determinizar → revertir → determinizar.
+// No necesita construir primero el DFA por subconjuntos del NFA original.
+func MinimizeBrzozowski(n *thompson.NFA, alphabet []rune) *DFA {
+	return Canonical(reverseDeterminize(NFAtoDFA(Reverse(n), alphabet)))
+}
+
+// minimizeBrzozowski minimiza un DFA ya construido con el algoritmo de Brzozowski.
+func minimizeBrzozowski(dfa *DFA) *DFA {
+	return reverseDeterminize(reverseDeterminize(dfa))
+}
+
+// reverseDeterminize calcula det(rev(dfa)) directamente sobre el DFA: el estado inicial es el
+// conjunto de estados de aceptación y un subconjunto acepta si contiene el estado inicial original.
+// No se agrega un estado inicial auxiliar (como haría Reverse(FromDFA(dfa))), porque ese estado
+// aparecería en el subconjunto inicial y el resultado dejaría de ser mínimo.
+func reverseDeterminize(dfa *DFA) *DFA {
+	index := make(map[string]int, len(dfa.States))
+	for i, state := range dfa.States {
+		index[state] = i
+	}
+
+	// pred[sym][j] = estados i tales que δ(i, sym) = j
+	pred := make(map[rune][][]int, len(dfa.Alphabet))
+	for _, sym := range dfa.Alphabet {
+		pred[sym] = make([][]int, len(dfa.States))
+	}
+	for i, state := range dfa.States {
+		for _, sym := range dfa.Alphabet {
+			if next, ok := dfa.Transitions[state][sym]; ok {
+				if j, ok := index[next]; ok {
+					pred[sym][j] = append(pred[sym][j], i)
+				}
+			}
+		}
+	}
+
+	// key genera un nombre único para un subconjunto (índices ordenados)
+	key := func(set []int) string {
+		sort.Ints(set)
+		str := ""
+		for _, i := range set {
+			str += fmt.Sprintf("%d_", i)
+		}
+		return str
+	}
+
+	startSet := []int{}
+	for i, state := range dfa.States {
+		if dfa.Accepting[state] {
+			startSet = append(startSet, i)
+		}
+	}
+	startName := key(startSet)
+
+	result := &DFA{
+		States:      []string{startName},
+		Alphabet:    dfa.Alphabet,
+		Transitions: map[string]map[rune]string{},
+		Start:       startName,
+		Accepting:   map[string]bool{},
+	}
+	seen := map[string]bool{startName: true}
+	queue := [][]int{startSet}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		currentName := key(current)
+		result.Transitions[currentName] = map[rune]string{}
+
+		for _, i := range current {
+			if dfa.States[i] == dfa.Start {
+				result.Accepting[currentName] = true
+			}
+		}
+
+		for _, sym := range dfa.Alphabet {
+			inNext := map[int]bool{}
+			next := []int{}
+			for _, j := range current {
+				for _, i := range pred[sym][j] {
+					if !inNext[i] {
+						inNext[i] = true
+						next = append(next, i)
+					}
+				}
+			}
+			if len(next) == 0 {
+				continue // Conjunto vacío: transición omitida, igual que en NFAtoDFA
+			}
+			nextName := key(next)
+			if !seen[nextName] {
+				seen[nextName] = true
+				result.States = append(result.States, nextName)
+				queue = append(queue, next)
+			}
+			result.Transitions[currentName][sym] = nextName
+		}
+	}
+
+	return result
+}
+
+// sortedStates retorna los estados del NFA ordenados por ID.
+func sortedStates(n *thompson.NFA) []*thompson.State {
+	states := append([]*thompson.State{}, n.States...)
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+	return states
+}
+
+// sortedSymbols retorna los símbolos de un mapa de transiciones en orden ascendente.
+func sortedSymbols(trans map[rune][]*thompson.State) []rune {
+	syms := make([]rune, 0, len(trans))
+	for sym := range trans {
+		syms = append(syms, sym)
+	}
+	sort.Slice(syms, func(i, j int) bool { return syms[i] < syms[j] })
+	return syms
+}
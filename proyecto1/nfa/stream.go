@@ -0,0 +1,114 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la simulación símbolo a símbolo para entradas que llegan como flujo.
+package nfa
+
+import "proyecto1/thompson"
+
+// Runner simula un autómata consumiendo la entrada de a un símbolo, sin necesidad de tener la cadena
+// completa (por ejemplo, al leer de un bufio.Reader).
+type Runner interface {
+	Reset()          // Vuelve al estado inicial (entrada vacía)
+	Step(sym rune)   // Consume un símbolo
+	Accepting() bool // Si la entrada consumida hasta ahora es aceptada
+	Dead() bool      // Si ninguna continuación de la entrada consumida puede ser aceptada
+}
+
+// Run simula la cadena completa con el Runner, desde el estado inicial, y retorna si es aceptada.
+func Run(r Runner, input string) bool {
+	r.Reset()
+	for _, sym := range input {
+		r.Step(sym)
+	}
+	return r.Accepting()
+}
+
+// DFARunner es un Runner sobre un DFA: cada paso es una consulta a la tabla de transiciones, de modo que
+// procesar una entrada toma tiempo lineal en su longitud.
+type DFARunner struct {
+	dfa   *DFA
+	live  map[string]bool
+	state string // "" = estado muerto (transición faltante o sin camino a la aceptación)
+}
+
+// NewDFARunner crea un Runner sobre el DFA, posicionado en el estado inicial.
+// Precalcula los estados vivos para que Dead detecte el rechazo en cuanto es inevitable.
+func NewDFARunner(dfa *DFA) *DFARunner {
+	r := &DFARunner{dfa: dfa, live: liveStates(dfa)}
+	r.Reset()
+	return r
+}
+
+// Reset vuelve al estado inicial.
+func (r *DFARunner) Reset() {
+	r.state = ""
+	if r.live[r.dfa.Start] {
+		r.state = r.dfa.Start
+	}
+}
+
+// Step consume un símbolo. Un símbolo sin transición lleva al estado muerto.
+func (r *DFARunner) Step(sym rune) {
+	if r.state == "" {
+		return
+	}
+	if next := r.dfa.Transitions[r.state][sym]; r.live[next] {
+		r.state = next
+	} else {
+		r.state = ""
+	}
+}
+
+// Accepting indica si el estado actual es de aceptación.
+func (r *DFARunner) Accepting() bool { return r.state != "" && r.dfa.Accepting[r.state] }
+
+// Dead indica si el DFA ya no puede aceptar.
+func (r *DFARunner) Dead() bool { return r.state == "" }
+
+// State retorna el estado actual del DFA ("" si está muerto).
+func (r *DFARunner) State() string { return r.state }
+
+// NFARunner es un Runner sobre un NFA: mantiene el conjunto de estados activos, como Simulate.
+type NFARunner struct {
+	nfa     *thompson.NFA
+	current stateSet
+}
+
+// NewNFARunner crea un Runner sobre el NFA, posicionado en la clausura ε del estado inicial.
+func NewNFARunner(n *thompson.NFA) *NFARunner {
+	r := &NFARunner{nfa: n}
+	r.Reset()
+	return r
+}
+
+// Reset vuelve a la clausura ε del estado inicial.
+func (r *NFARunner) Reset() {
+	r.current = make(stateSet)
+	add(r.current, r.nfa.Start)
+	r.current = epsilonClosure(r.current)
+}
+
+// Step consume un símbolo.
+func (r *NFARunner) Step(sym rune) { r.current = epsilonClosure(move(r.current, sym)) }
+
+// Accepting indica si el estado de aceptación está entre los activos.
+func (r *NFARunner) Accepting() bool {
+	_, ok := r.current[r.nfa.Accept]
+	return ok
+}
+
+// Dead indica si no quedan estados activos.
+func (r *NFARunner) Dead() bool { return len(r.current) == 0 }
+
+// Unanchored construye un NFA para Σ*·L(a): acepta las cadenas con un sufijo en L(a). Al simularlo de
+// izquierda a derecha, la entrada contiene una subcadena en L(a) si y solo si algún prefijo es aceptado,
+// así que la búsqueda de subcadenas se reduce a una sola pasada.
+func Unanchored(a Automaton, alphabet []rune) *thompson.NFA {
+	bld := &nfaBuilder{}
+	s := bld.newState()
+	n, copies := bld.clone(a)
+	for _, sym := range sortedAlphabet(alphabet) {
+		s.AddEdge(sym, s)
+	}
+	s.AddEdge(thompson.Epsilon, copies[n.Start])
+	return bld.build(s, copies[n.Accept])
+}
@@ -0,0 +1,64 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo trazas paso a paso de la simulación de una cadena.
+package nfa
+
+import (
+	"fmt"
+	"proyecto1/thompson"
+	"sort"
+)
+
+// TraceStep es un paso de una simulación: el símbolo leído y los estados activos después de leerlo.
+// El primer paso (Symbol == 0) contiene los estados iniciales antes de leer la cadena.
+type TraceStep struct {
+	Symbol rune
+	States []string // En un NFA, q<ID> ordenados; en un DFA, el estado actual (vacío si no hay transición)
+}
+
+// TraceNFA simula la cadena en el NFA como Simulate y retorna, para cada símbolo, el conjunto de
+// estados activos (cierre ε incluido), junto con el veredicto.
+func TraceNFA(n *thompson.NFA, input string) ([]TraceStep, bool) {
+	current := make(stateSet)
+	add(current, n.Start)
+	current = epsilonClosure(current)
+	steps := []TraceStep{{States: stateNames(current)}}
+	for _, r := range input {
+		current = epsilonClosure(move(current, r))
+		steps = append(steps, TraceStep{Symbol: r, States: stateNames(current)})
+	}
+	_, ok := current[n.Accept]
+	return steps, ok
+}
+
+// stateNames retorna los nombres q<ID> del conjunto, ordenados por ID.
+func stateNames(set stateSet) []string {
+	ids := make([]int, 0, len(set))
+	for s := range set {
+		ids = append(ids, s.ID)
+	}
+	sort.Ints(ids)
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = fmt.Sprintf("q%d", id)
+	}
+	return names
+}
+
+// TraceDFA simula la cadena en el DFA como SimulateDFA y retorna el estado después de cada símbolo,
+// junto con el veredicto. Si falta una transición, los pasos restantes quedan sin estados.
+func TraceDFA(dfa *DFA, input string) ([]TraceStep, bool) {
+	state, ok := dfa.Start, dfa.Start != ""
+	steps := []TraceStep{{States: []string{dfa.Start}}}
+	for _, r := range input {
+		if ok {
+			state, ok = dfa.Transitions[state][r]
+			ok = ok && state != ""
+		}
+		step := TraceStep{Symbol: r}
+		if ok {
+			step.States = []string{state}
+		}
+		steps = append(steps, step)
+	}
+	return steps, ok && dfa.Accepting[state]
+}
@@ -0,0 +1,73 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la eliminación de estados inalcanzables y muertos de un DFA (trim).
+package nfa
+
+// Trim retorna una copia del DFA sin estados inalcanzables ni estados muertos
+// (desde los que no se alcanza ningún estado de aceptación), junto con las transiciones hacia ellos.
+// Es la operación inversa de Complete: el DFA resultante puede ser parcial.
+// El estado inicial se conserva siempre, aunque el lenguaje sea vacío.
+func Trim(dfa *DFA) *DFA {
+	reachable := getReachableStates(dfa)
+
+	live := liveStates(dfa)
+
+	keep := func(state string) bool {
+		return state == dfa.Start || (reachable[state] && live[state])
+	}
+
+	result := &DFA{
+		Alphabet:    dfa.Alphabet,
+		Transitions: map[string]map[rune]string{},
+		Start:       dfa.Start,
+		Accepting:   map[string]bool{},
+	}
+	for _, state := range dfa.States {
+		if !keep(state) {
+			continue
+		}
+		result.States = append(result.States, state)
+		row := map[rune]string{}
+		for _, sym := range dfa.Alphabet {
+			if to, ok := dfa.Transitions[state][sym]; ok && keep(to) && live[to] {
+				row[sym] = to
+			}
+		}
+		result.Transitions[state] = row
+		if dfa.Accepting[state] {
+			result.Accepting[state] = true
+		}
+	}
+	return result
+}
+
+// liveStates retorna los estados desde los que se alcanza algún estado de aceptación
+// (BFS hacia atrás desde los estados de aceptación).
+func liveStates(dfa *DFA) map[string]bool {
+	pred := map[string][]string{}
+	for _, from := range dfa.States {
+		for _, sym := range dfa.Alphabet {
+			if to, ok := dfa.Transitions[from][sym]; ok {
+				pred[to] = append(pred[to], from)
+			}
+		}
+	}
+	live := map[string]bool{}
+	queue := []string{}
+	for _, state := range dfa.States {
+		if dfa.Accepting[state] {
+			live[state] = true
+			queue = append(queue, state)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, p := range pred[state] {
+			if !live[p] {
+				live[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	return live
+}
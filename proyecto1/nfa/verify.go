@@ -0,0 +1,91 @@
+// Package nfa provee funcionalidad para trabajar con autómatas finitos,
+// incluyendo la verificación cruzada de los veredictos del NFA, el DFA y el DFA minimizado.
+package nfa
+
+import "proyecto1/thompson"
+
+// Verdicts son los veredictos del NFA, el DFA y el DFA minimizado para una cadena.
+type Verdicts struct {
+	Word   string `json:"word"`
+	NFA    bool   `json:"nfa"`      // El NFA con el simulador de NewSimulator (por bits si cabe en un BitNFA)
+	Sets   bool   `json:"nfa_sets"` // El NFA simulado por conjuntos con Simulate, la referencia
+	DFA    bool   `json:"dfa"`
+	MinDFA bool   `json:"min_dfa"`
+}
+
+// Agree indica si los tres autómatas (y las dos simulaciones del NFA) coinciden.
+func (v Verdicts) Agree() bool {
+	return v.NFA == v.Sets && v.NFA == v.DFA && v.DFA == v.MinDFA
+}
+
+// CrossCheck evalúa la cadena en los tres autómatas. El NFA se simula con NewSimulator y con Simulate.
+// Construye el simulador en cada llamada; para evaluar varias cadenas conviene CrossCheckWith.
+func CrossCheck(n *thompson.NFA, dfa, minDFA *DFA, input string) Verdicts {
+	return CrossCheckWith(NewSimulator(n), n, dfa, minDFA, input)
+}
+
+// CrossCheckWith es CrossCheck con un simulador del NFA ya construido (ver NewSimulator).
+func CrossCheckWith(sim Runner, n *thompson.NFA, dfa, minDFA *DFA, input string) Verdicts {
+	return Verdicts{Word: input, NFA: Run(sim, input), Sets: Simulate(n, input), DFA: SimulateDFA(dfa, input), MinDFA: SimulateDFA(minDFA, input)}
+}
+
+// CrossCheckUpTo evalúa en los tres autómatas todas las cadenas sobre el alfabeto de longitud 0 a maxLen,
+// en orden canónico (por longitud y luego lexicográfico), y retorna la primera en la que no coinciden.
+// El segundo valor es la cantidad de cadenas evaluadas; el tercero es false si todos coinciden en todas.
+// Cada longitud se recorre en profundidad desde los estados iniciales (profundización iterativa, para que la
+// primera discrepancia sea la más corta); dentro de un recorrido, los prefijos comunes se simulan una sola
+// vez, avanzando los estados símbolo a símbolo.
+// Si el NFA cabe en un BitNFA, se compara también la simulación por bits con la de Simulate.
+func CrossCheckUpTo(n *thompson.NFA, dfa, minDFA *DFA, alphabet []rune, maxLen int) (Verdicts, int, bool) {
+	alphabet = sortedAlphabet(alphabet)
+	start := make(stateSet)
+	add(start, n.Start)
+	start = epsilonClosure(start)
+	bn, _ := NewBitNFA(n, DefaultBitsetWords) // nil: el NFA es demasiado grande
+	var bitStart bitset
+	if bn != nil {
+		bitStart = bn.start
+	}
+
+	checked := 0
+	var found Verdicts
+	word := make([]rune, 0, maxLen)
+
+	// dfs recorre los prefijos de longitud depth hasta target; retorna true al encontrar una discrepancia.
+	// Un estado "" del DFA representa una transición faltante (rechazo).
+	var dfs func(set stateSet, bs bitset, d, m string, target int) bool
+	dfs = func(set stateSet, bs bitset, d, m string, target int) bool {
+		if len(word) == target {
+			checked++
+			_, inNFA := set[n.Accept]
+			v := Verdicts{Word: string(word), NFA: inNFA, Sets: inNFA, DFA: d != "" && dfa.Accepting[d], MinDFA: m != "" && minDFA.Accepting[m]}
+			if bn != nil {
+				v.NFA = bs.has(bn.accept)
+			}
+			if !v.Agree() {
+				found = v
+				return true
+			}
+			return false
+		}
+		for _, sym := range alphabet {
+			word = append(word, sym)
+			var nextBits bitset
+			if bn != nil {
+				nextBits = bn.step(bs, sym, make(bitset, len(bs)))
+			}
+			stop := dfs(epsilonClosure(move(set, sym)), nextBits, dfa.Transitions[d][sym], minDFA.Transitions[m][sym], target)
+			word = word[:len(word)-1]
+			if stop {
+				return true
+			}
+		}
+		return false
+	}
+	for length := 0; length <= maxLen; length++ {
+		if dfs(start, bitStart, dfa.Start, minDFA.Start, length) {
+			return found, checked, true
+		}
+	}
+	return Verdicts{}, checked, false
+}
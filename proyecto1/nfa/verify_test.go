@@ -0,0 +1,35 @@
+package nfa
+
+import "testing"
+
+func TestCrossCheckUpToFindsDivergence(t *testing.T) {
+	tests := []struct {
+		regex, other string // other da el DFA "minimizado" equivocado
+		first        string // Primera cadena, en orden canónico, en la que difieren
+		checked      int    // Cadenas evaluadas hasta encontrarla
+	}{
+		{"(a|b)*abb", "(a|b)*ab", "ab", 5},
+		{"a*", "a*|b", "b", 3},
+		{"ab", "a", "a", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			n := buildNFA(t, tt.regex)
+			alphabet := []rune("ab")
+			wrong := MinimizeDFA(NFAtoDFA(buildNFA(t, tt.other), alphabet))
+			first, checked, diverges := CrossCheckUpTo(n, NFAtoDFA(n, alphabet), wrong, alphabet, 5)
+			if !diverges {
+				t.Fatalf("no se encontró la discrepancia con %s", tt.other)
+			}
+			if first.Word != tt.first || checked != tt.checked {
+				t.Errorf("primera discrepancia %q tras %d cadenas, se esperaba %q tras %d", first.Word, checked, tt.first, tt.checked)
+			}
+			if first.Agree() || first.NFA != first.DFA || first.NFA == first.MinDFA {
+				t.Errorf("veredictos inesperados %+v", first)
+			}
+			if v := CrossCheck(n, NFAtoDFA(n, alphabet), wrong, tt.first); v != first {
+				t.Errorf("CrossCheck = %+v, CrossCheckUpTo = %+v", v, first)
+			}
+		})
+	}
+}
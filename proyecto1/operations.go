@@ -0,0 +1,274 @@
+// /proyecto1/operations.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"proyecto1/config"
+	"proyecto1/jflap"
+	"proyecto1/nfa"
+	"proyecto1/regex"
+	"proyecto1/thompson"
+)
+
+// closureOp describe una operación de cerradura invocable desde la línea de comandos.
+// Las operaciones binarias se pliegan por la izquierda cuando hay más de dos operandos;
+// las operaciones con mapeo (homomorfismos y sustituciones) leen el archivo indicado con -map.
+type closureOp struct {
+	unary   func(a nfa.Automaton, sigma []rune) nfa.Automaton
+	binary  func(a, b nfa.Automaton, sigma []rune) nfa.Automaton
+	mapping func(a nfa.Automaton, m map[rune]string) (nfa.Automaton, error)
+}
+
+// closureOps asocia el nombre de cada operación (flag -op) con su implementación en el paquete nfa.
+var closureOps = map[string]closureOp{
+	"union":      {binary: func(a, b nfa.Automaton, _ []rune) nfa.Automaton { return nfa.Union(a, b) }},
+	"concat":     {binary: func(a, b nfa.Automaton, _ []rune) nfa.Automaton { return nfa.Concat(a, b) }},
+	"intersect":  {binary: func(a, b nfa.Automaton, s []rune) nfa.Automaton { return nfa.Intersection(a, b, s) }},
+	"diff":       {binary: func(a, b nfa.Automaton, s []rune) nfa.Automaton { return nfa.Difference(a, b, s) }},
+	"symdiff":    {binary: func(a, b nfa.Automaton, s []rune) nfa.Automaton { return nfa.SymmetricDifference(a, b, s) }},
+	"rquot":      {binary: func(a, b nfa.Automaton, s []rune) nfa.Automaton { return nfa.RightQuotient(a, b, s) }},
+	"lquot":      {binary: func(a, b nfa.Automaton, s []rune) nfa.Automaton { return nfa.LeftQuotient(a, b, s) }},
+	"star":       {unary: func(a nfa.Automaton, _ []rune) nfa.Automaton { return nfa.Star(a) }},
+	"reverse":    {unary: func(a nfa.Automaton, _ []rune) nfa.Automaton { return nfa.Reverse(a) }},
+	"complement": {unary: func(a nfa.Automaton, s []rune) nfa.Automaton { return nfa.Complement(a, s) }},
+	"prefix":     {unary: func(a nfa.Automaton, _ []rune) nfa.Automaton { return nfa.Prefix(a) }},
+	"suffix":     {unary: func(a nfa.Automaton, _ []rune) nfa.Automaton { return nfa.Suffix(a) }},
+	"infix":      {unary: func(a nfa.Automaton, _ []rune) nfa.Automaton { return nfa.Infix(a) }},
+	"hom":        {mapping: applyHomomorphism},
+	"invhom":     {mapping: applyInverseHomomorphism},
+	"subst":      {mapping: applySubstitution},
+}
+
+// homomorphismFromMapping valida que cada imagen sea una cadena de símbolos (no una regex).
+func homomorphismFromMapping(m map[rune]string) (nfa.Homomorphism, error) {
+	h := nfa.Homomorphism{}
+	for sym, image := range m {
+		for _, c := range image {
+			if !config.IsAlphanumeric(c) || c == 'ε' {
+				return nil, fmt.Errorf("la imagen de %q (%q) no es una cadena de símbolos; use -op subst para imágenes regex", sym, image)
+			}
+		}
+		h[sym] = image
+	}
+	return h, nil
+}
+
+// applyHomomorphism calcula h(L) con el homomorfismo del archivo de mapeo.
+func applyHomomorphism(a nfa.Automaton, m map[rune]string) (nfa.Automaton, error) {
+	h, err := homomorphismFromMapping(m)
+	if err != nil {
+		return nil, err
+	}
+	return nfa.ApplyHomomorphism(a, h)
+}
+
+// applyInverseHomomorphism calcula h⁻¹(L) con el homomorfismo del archivo de mapeo.
+func applyInverseHomomorphism(a nfa.Automaton, m map[rune]string) (nfa.Automaton, error) {
+	h, err := homomorphismFromMapping(m)
+	if err != nil {
+		return nil, err
+	}
+	return nfa.InverseHomomorphism(a, h), nil
+}
+
+// applySubstitution calcula s(L), compilando cada lado derecho del mapeo como regex.
+func applySubstitution(a nfa.Automaton, m map[rune]string) (nfa.Automaton, error) {
+	sub := nfa.Substitution{}
+	for sym, r := range m {
+		if r == "" {
+			r = "ε"
+		}
+		c, err := compileNFA(r, nil)
+		if err != nil {
+			return nil, fmt.Errorf("sustitución de %q: %v", sym, err)
+		}
+		sub[sym] = c.NFA
+	}
+	return nfa.Substitute(a, sub)
+}
+
+// closureOpNames retorna los nombres de las operaciones disponibles, ordenados.
+func closureOpNames() []string {
+	names := make([]string, 0, len(closureOps))
+	for name := range closureOps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runClosureOp aplica la operación opName a las regex dadas como operandos, exporta el resultado
+// y evalúa las cadenas de wordsCSV (ver emitAutomaton). Las regex se leen en la sintaxis de opts.syntax.
+func runClosureOp(opName string, operands []string, sigmaSpec, mapPath, wordsCSV string, opts lineOptions) error {
+	op, ok := closureOps[opName]
+	if !ok {
+		return fmt.Errorf("operación desconocida %q (disponibles: %s)", opName, strings.Join(closureOpNames(), ", "))
+	}
+	if (op.unary != nil || op.mapping != nil) && len(operands) != 1 {
+		return fmt.Errorf("la operación %s requiere exactamente 1 regex (se recibieron %d)", opName, len(operands))
+	}
+	if op.binary != nil && len(operands) < 2 {
+		return fmt.Errorf("la operación %s requiere 2 o más regex (se recibieron %d)", opName, len(operands))
+	}
+
+	// Compilar operandos y calcular Σ (declarado o unión de los símbolos de las regex)
+	var sigma []rune
+	if sigmaSpec != "" {
+		s, err := config.ParseAlphabet(sigmaSpec)
+		if err != nil {
+			return fmt.Errorf("alfabeto inválido: %v", err)
+		}
+		sigma = s
+	}
+	declared := sigma != nil
+	automata := make([]nfa.Automaton, 0, len(operands))
+	for _, r := range operands {
+		if opts.syntax != regex.NativeSyntax {
+			imported, err := regex.Import(r, opts.syntax)
+			if err != nil {
+				return fmt.Errorf("regex %q: error de importación: %v", r, err)
+			}
+			r = imported.String()
+		}
+		// Con Σ declarado, compileNFA valida la regex contra Σ; si no, Σ es la unión de sus alfabetos
+		var operandSigma []rune
+		if declared {
+			operandSigma = sigma
+		}
+		c, err := compileNFA(r, operandSigma)
+		if err != nil {
+			return fmt.Errorf("regex %q: %v", r, err)
+		}
+		if !declared {
+			for _, sym := range c.Alphabet {
+				if !config.ContainsRune(sigma, sym) {
+					sigma = append(sigma, sym)
+				}
+			}
+		}
+		automata = append(automata, c.NFA)
+	}
+
+	// Aplicar la operación
+	var result nfa.Automaton
+	switch {
+	case op.mapping != nil:
+		if mapPath == "" {
+			return fmt.Errorf("la operación %s requiere un archivo de mapeo (-map)", opName)
+		}
+		f, err := os.Open(mapPath)
+		if err != nil {
+			return err
+		}
+		m, err := config.ParseMapping(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", mapPath, err)
+		}
+		if result, err = op.mapping(automata[0], m); err != nil {
+			return err
+		}
+		// El resultado está sobre el alfabeto de destino del mapeo
+		sigma = nfa.Alphabet(result)
+	case op.unary != nil:
+		result = op.unary(automata[0], sigma)
+	default:
+		result = automata[0]
+		for _, next := range automata[1:] {
+			result = op.binary(result, next, sigma)
+		}
+	}
+
+	logOut := log.New(os.Stdout, "", 0)
+	logOut.Printf("Operación: %s\n", opName)
+	for i, r := range operands {
+		logOut.Printf("  Operando %d: %s\n", i+1, r)
+	}
+	logOut.Printf("  Alfabeto: %s\n", config.FormatAlphabet(sigma))
+
+	return emitAutomaton(logOut, "op_"+opName, result, sigma, wordsCSV, opts)
+}
+
+// emitAutomaton completa las etapas del autómata resultado con automatonPipeline (minimizando con
+// opts.minAlg), las exporta con el prefijo name (ver exportStage) y evalúa las cadenas de wordsCSV en
+// cada etapa. Como en el procesamiento de cada línea, -complete y -trim se aplican a los DFAs después de
+// minimizar, y con -verify se señalan las discrepancias y se comparan los autómatas en las cadenas cortas;
+// si no coinciden, retorna un error.
+func emitAutomaton(logOut *log.Logger, name string, result nfa.Automaton, sigma []rune, wordsCSV string, opts lineOptions) error {
+	out := opts.out
+	_ = os.MkdirAll(out.dotDir, 0o755)
+	_ = os.MkdirAll(out.pngDir, 0o755)
+
+	c, err := automatonPipeline(result, sigma, opts.minAlg)
+	if err != nil {
+		return err
+	}
+	_, isNFA := result.(*thompson.NFA)
+	if isNFA {
+		logOut.Printf("  Estados NFA: %d\n", len(c.NFA.States))
+		exportStage(context.Background(), logOut, " NFA", name+"_nfa", c.NFA, out.layout, out)
+	}
+	logOut.Printf("  Estados DFA: %d\n", len(c.DFA.States))
+	logOut.Printf("  Estados DFA minimizado: %d\n", len(c.MinDFA.States))
+
+	// DFAs totales (con estado trampa) o recortados, según las flags
+	if opts.complete {
+		c.DFA = nfa.Complete(c.DFA, sigma)
+		c.MinDFA = nfa.Complete(c.MinDFA, sigma)
+	} else if opts.trim {
+		c.DFA = nfa.Trim(c.DFA)
+		c.MinDFA = nfa.Trim(c.MinDFA)
+	}
+
+	// Las coordenadas solo corresponden al autómata de entrada, no a los derivados
+	var dfaLayout jflap.Layout
+	if !isNFA {
+		dfaLayout = out.layout
+	}
+	exportStage(context.Background(), logOut, " DFA", name+"_dfa", c.DFA, dfaLayout, out)
+	exportStage(context.Background(), logOut, " DFA minimizado", name+"_min_dfa", c.MinDFA, nil, out)
+
+	// Evaluar cadenas
+	diverged := false
+	if wordsCSV != "" {
+		for i, tok := range strings.Split(wordsCSV, ",") {
+			w := config.ParseWord(strings.TrimSpace(tok))
+			logOut.Printf("  Caso %d: w = %q\n", i+1, w)
+			v := nfa.CrossCheckWith(c.Sim, c.NFA, c.DFA, c.MinDFA, w)
+			if isNFA {
+				logOut.Printf("    w ∈ L(NFA)?   %s\n", yesNo(v.NFA))
+			}
+			logOut.Printf("    w ∈ L(DFA)?   %s\n", yesNo(v.DFA))
+			logOut.Printf("    w ∈ L(minDFA)? %s\n", yesNo(v.MinDFA))
+			if opts.verify && !v.Agree() {
+				logOut.Printf("    ¡Los autómatas no coinciden en esta cadena! (%s)\n", describeVerdicts(v))
+				diverged = true
+			}
+		}
+	}
+
+	// Verificación exhaustiva sobre todas las cadenas cortas
+	if opts.verify {
+		first, checked, diverges := nfa.CrossCheckUpTo(c.NFA, c.DFA, c.MinDFA, sigma, opts.verifyLen)
+		logOut.Printf("  Verificación: %s\n", describeCrossCheck(first, checked, diverges, sigma, opts.verifyLen))
+		diverged = diverged || diverges
+	}
+	if diverged {
+		return fmt.Errorf("los autómatas no coinciden")
+	}
+	return nil
+}
+
+// yesNo traduce un veredicto de aceptación a "sí"/"no".
+func yesNo(accepted bool) string {
+	if accepted {
+		return "sí"
+	}
+	return "no"
+}
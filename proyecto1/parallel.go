@@ -0,0 +1,146 @@
+// /proyecto1/parallel.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+)
+
+// lineOutput acumula la salida de una línea mientras se procesa, para escribirla completa y en orden
+// cuando le toca. Cada fragmento recuerda si va solo a la consola o también al archivo de salida.
+type lineOutput struct {
+	chunks []outputChunk
+}
+
+// outputChunk es un mensaje de log de una línea.
+type outputChunk struct {
+	text        string
+	consoleOnly bool
+}
+
+// chunkWriter agrega a la salida de la línea lo que escribe un log.Logger.
+type chunkWriter struct {
+	out         *lineOutput
+	consoleOnly bool
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	w.out.chunks = append(w.out.chunks, outputChunk{text: string(p), consoleOnly: w.consoleOnly})
+	return len(p), nil
+}
+
+// loggers retorna los logs de la línea: consola + archivo, y solo consola.
+func (o *lineOutput) loggers() (logBoth, logConsole *log.Logger) {
+	return log.New(chunkWriter{out: o}, "", 0), log.New(chunkWriter{out: o, consoleOnly: true}, "", 0)
+}
+
+// flush escribe la salida acumulada en la consola y, salvo los mensajes solo de consola, en el archivo.
+func (o *lineOutput) flush(console, file io.Writer) error {
+	for _, c := range o.chunks {
+		if _, err := io.WriteString(console, c.text); err != nil {
+			return err
+		}
+		if c.consoleOnly {
+			continue
+		}
+		if _, err := io.WriteString(file, c.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineResult es lo que produce el procesamiento de una línea de la entrada.
+type lineResult struct {
+	output   lineOutput
+	report   *reportLine  // Nil en las líneas mal formadas
+	sum      suiteSummary // Casos con veredicto esperado de la línea
+	diverged bool         // Con -verify, si los autómatas no coinciden en alguna cadena
+}
+
+// lineJob es una línea leída, en espera de su resultado.
+type lineJob struct {
+	in     inputLine
+	result chan *lineResult // Con capacidad 1: el trabajador nunca se bloquea al entregar
+}
+
+// processLines lee la entrada con scanInput y procesa las líneas válidas con process en workers goroutines.
+// emit recibe los resultados en el orden de la entrada (también los de las líneas mal formadas), de modo
+// que la salida es la misma que con un solo trabajador. El primer error fatal (de lectura o de emit)
+// cancela el contexto: no se empiezan más líneas, las que están en curso se descartan y se retorna ese error.
+func processLines(ctx context.Context, r io.Reader, workers int, process func(ctx context.Context, in inputLine) *lineResult, emit func(res *lineResult) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	jobs := make(chan *lineJob)
+	order := make(chan *lineJob, workers) // Trabajos en el orden de lectura
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					job.result <- nil
+					continue
+				}
+				job.result <- process(ctx, job.in)
+			}
+		}()
+	}
+
+	// Lector: encola cada línea primero en order (para emitirla en su turno) y después para los trabajadores
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(order)
+		defer close(jobs)
+		enqueue := func(job *lineJob, work bool) {
+			select {
+			case order <- job:
+			case <-ctx.Done():
+				return
+			}
+			if work {
+				select {
+				case jobs <- job:
+				case <-ctx.Done():
+					job.result <- nil
+				}
+			}
+		}
+		err := scanInput(r, func(in inputLine) {
+			enqueue(&lineJob{in: in, result: make(chan *lineResult, 1)}, true)
+		}, func(lineNo int, msg string) {
+			res := &lineResult{}
+			_, logConsole := res.output.loggers()
+			logConsole.Printf("Línea %d: %s\n", lineNo, msg)
+			job := &lineJob{result: make(chan *lineResult, 1)}
+			job.result <- res
+			enqueue(job, false)
+		})
+		if err != nil {
+			cancel(fmt.Errorf("error al leer la entrada: %v", err))
+		}
+	}()
+
+	for job := range order {
+		var res *lineResult
+		select {
+		case res = <-job.result:
+		case <-ctx.Done():
+		}
+		if res == nil || ctx.Err() != nil {
+			continue // Cancelado: se vacía order para que el lector termine
+		}
+		if err := emit(res); err != nil {
+			cancel(err)
+		}
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
@@ -0,0 +1,174 @@
+// /proyecto1/pipeline.go
+package main
+
+import (
+	"fmt"
+
+	"proyecto1/config"
+	"proyecto1/jflap"
+	"proyecto1/nfa"
+	"proyecto1/regex"
+	"proyecto1/thompson"
+)
+
+// compiled reúne todas las etapas del procesamiento de una regex: las formas textuales,
+// el AST, el alfabeto y los tres autómatas.
+type compiled struct {
+	Original, Expanded, Formatted, Postfix string
+	AST                                    *regex.Node
+	Alphabet                               []rune
+	NFA                                    *thompson.NFA
+	Sim                                    nfa.Runner // Simulador del NFA (nfa.NewSimulator), construido una vez
+	DFA, MinDFA                            *nfa.DFA
+
+	// Solo para autómatas cargados desde archivo (ver loadPipeline): el autómata tal como se leyó
+	// y las coordenadas de sus estados si venía de JFLAP. Las formas textuales y el AST quedan vacíos.
+	Input  nfa.Automaton
+	Layout jflap.Layout
+}
+
+// compilePipeline procesa la regex igual que main: expande, formatea, pasa a postfija, construye el AST,
+// el NFA de Thompson, el DFA sobre sigma (o sobre los símbolos de la regex si sigma es nil) y lo minimiza
+// con el algoritmo indicado.
+func compilePipeline(r string, sigma []rune, minAlg nfa.MinimizeAlgorithm) (*compiled, error) {
+	c, err := compileNFA(r, sigma)
+	if err != nil {
+		return nil, err
+	}
+	c.DFA = nfa.NFAtoDFA(c.NFA, c.Alphabet)
+	if err := c.minimize(minAlg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// compileNFA hace las etapas de compilePipeline hasta el NFA de Thompson y el alfabeto, sin construir
+// el DFA (que puede tener un número exponencial de estados). DFA y MinDFA quedan en nil.
+func compileNFA(r string, sigma []rune) (*compiled, error) {
+	c := &compiled{Original: r}
+	c.Expanded = config.ExpandRegexExtensions(r)
+	c.Formatted = config.FormatRegex(c.Expanded)
+	c.Postfix = config.InfixToPostfix(c.Formatted)
+
+	var err error
+	if c.AST, err = regex.BuildAST(c.Postfix); err != nil {
+		return nil, fmt.Errorf("error de AST: %v", err)
+	}
+	if c.NFA, err = thompson.Build(c.AST); err != nil {
+		return nil, fmt.Errorf("error de Thompson: %v", err)
+	}
+	c.Sim = nfa.NewSimulator(c.NFA)
+
+	c.Alphabet = sigma
+	if c.Alphabet == nil {
+		c.Alphabet = config.RegexAlphabet(c.Formatted)
+	} else if err := config.ValidateRegexAlphabet(c.Formatted, c.Alphabet); err != nil {
+		return nil, fmt.Errorf("error de alfabeto: %v", err)
+	}
+	return c, nil
+}
+
+// minimize construye c.MinDFA a partir de c.DFA (o de c.NFA con Brzozowski).
+func (c *compiled) minimize(minAlg nfa.MinimizeAlgorithm) error {
+	var err error
+	if minAlg == nfa.Brzozowski {
+		c.MinDFA = nfa.MinimizeBrzozowski(c.NFA, c.Alphabet)
+	} else if c.MinDFA, err = nfa.MinimizeDFAWith(c.DFA, minAlg); err != nil {
+		return fmt.Errorf("error de minimización: %v", err)
+	}
+	return nil
+}
+
+// loadPipeline carga un autómata desde JSON o JFLAP (ver loadAutomaton) y completa las etapas que
+// faltan: el DFA por subconjuntos si se cargó un NFA y el DFA minimizado. Con sigma, los símbolos del
+// autómata deben pertenecer al alfabeto declarado.
+func loadPipeline(path string, sigma []rune, minAlg nfa.MinimizeAlgorithm) (*compiled, error) {
+	a, layout, err := loadAutomaton(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	alphabet := nfa.Alphabet(a)
+	if sigma != nil {
+		for _, sym := range alphabet {
+			if !config.ContainsRune(sigma, sym) {
+				return nil, fmt.Errorf("error de alfabeto: el autómata usa el símbolo %q, que no pertenece a Σ = %s", sym, config.FormatAlphabet(sigma))
+			}
+		}
+		alphabet = sigma
+	}
+	c, err := automatonPipeline(a, alphabet, minAlg)
+	if err != nil {
+		return nil, err
+	}
+	c.Original, c.Layout = path, layout
+	return c, nil
+}
+
+// automatonPipeline completa las etapas de un autómata ya construido (cargado o resultado de una
+// operación de cerradura) sobre alphabet: el NFA equivalente, el DFA por subconjuntos si a es un NFA
+// y el DFA minimizado.
+func automatonPipeline(a nfa.Automaton, alphabet []rune, minAlg nfa.MinimizeAlgorithm) (*compiled, error) {
+	c := &compiled{Input: a, NFA: a.AsNFA(), Alphabet: alphabet}
+	c.Sim = nfa.NewSimulator(c.NFA)
+	if d, ok := a.(*nfa.DFA); ok {
+		c.DFA = d
+	} else {
+		c.DFA = nfa.NFAtoDFA(c.NFA, c.Alphabet)
+	}
+	if err := c.minimize(minAlg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// stage retorna el autómata de una etapa por nombre: nfa, dfa, min o input (el autómata cargado,
+// o el NFA si se compiló desde una regex).
+func (c *compiled) stage(name string) (nfa.Automaton, error) {
+	switch name {
+	case "nfa":
+		return c.NFA, nil
+	case "dfa":
+		return c.DFA, nil
+	case "min":
+		return c.MinDFA, nil
+	case "input":
+		if c.Input != nil {
+			return c.Input, nil
+		}
+		return c.NFA, nil
+	}
+	return nil, fmt.Errorf("etapa desconocida %q (nfa, dfa, min o input)", name)
+}
+
+// match evalúa la cadena en los tres autómatas. Con sigma declarado, las cadenas con símbolos fuera
+// del alfabeto producen un error en lugar de un veredicto.
+func (c *compiled) match(w string, sigmaDeclared bool) (reportWord, error) {
+	if sigmaDeclared {
+		if err := config.ValidateWord(w, c.Alphabet); err != nil {
+			return reportWord{Word: w, Err: err.Error()}, err
+		}
+	}
+	v := nfa.CrossCheckWith(c.Sim, c.NFA, c.DFA, c.MinDFA, w)
+	return reportWord{Word: w, NFA: v.NFA, Sets: v.Sets, DFA: v.DFA, Min: v.MinDFA}, nil
+}
+
+// equivalence compara los lenguajes de a y b sobre sigma (o sobre la unión de sus alfabetos si es nil).
+// El resultado tiene la forma de /equivalent: "equivalent" y, si difieren, la cadena testigo más corta
+// ("witness") y el operando a cuyo lenguaje pertenece ("regex1" o "regex2").
+func equivalence(a, b *compiled, sigma []rune) map[string]any {
+	alphabet := sigma
+	if alphabet == nil {
+		alphabet = append(append([]rune{}, a.Alphabet...), b.Alphabet...)
+	}
+	equivalent, witness := nfa.Equivalent(a.MinDFA, b.MinDFA, alphabet)
+	resp := map[string]any{"equivalent": equivalent}
+	if !equivalent {
+		resp["witness"] = witness
+		if nfa.SimulateDFA(a.MinDFA, witness) {
+			resp["in"] = "regex1"
+		} else {
+			resp["in"] = "regex2"
+		}
+	}
+	return resp
+}
@@ -0,0 +1,45 @@
+package regex
+
+// Size retorna la cantidad de nodos del AST.
+func (n *Node) Size() int {
+	if n == nil {
+		return 0
+	}
+	return 1 + n.Left.Size() + n.Right.Size()
+}
+
+// Width retorna el ancho alfabético: la cantidad de apariciones de símbolos (literales distintos de ε).
+// Es la cantidad de posiciones de la regex, y por lo tanto los estados del autómata de Glushkov menos uno.
+func (n *Node) Width() int {
+	if n == nil {
+		return 0
+	}
+	if n.Kind == Literal {
+		if n.Val == 'ε' {
+			return 0
+		}
+		return 1
+	}
+	return n.Left.Width() + n.Right.Width()
+}
+
+// StarHeight retorna la altura de estrella: la máxima cantidad de estrellas anidadas.
+func (n *Node) StarHeight() int {
+	if n == nil {
+		return 0
+	}
+	h := max(n.Left.StarHeight(), n.Right.StarHeight())
+	if n.Kind == Star {
+		h++
+	}
+	return h
+}
+
+// Depth retorna la profundidad de anidamiento: la cantidad de nodos del camino más largo de la raíz
+// a una hoja (1 para un literal).
+func (n *Node) Depth() int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(n.Left.Depth(), n.Right.Depth())
+}
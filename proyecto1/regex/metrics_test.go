@@ -0,0 +1,39 @@
+package regex
+
+import "testing"
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		regex                          string
+		size, width, starHeight, depth int
+	}{
+		{"a", 1, 1, 0, 1},
+		{"ε", 1, 0, 0, 1},
+		{"ab*", 4, 2, 1, 3},
+		{"(a|b)*abb", 10, 5, 1, 6},
+		{"((a*)*|b)c", 7, 3, 2, 5},
+		{"a(b|c)+", 10, 5, 1, 4}, // + se expande: a(b|c)(b|c)*
+	}
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			n := parse(t, tt.regex)
+			if got := n.Size(); got != tt.size {
+				t.Errorf("Size = %d, se esperaba %d", got, tt.size)
+			}
+			if got := n.Width(); got != tt.width {
+				t.Errorf("Width = %d, se esperaba %d", got, tt.width)
+			}
+			if got := n.StarHeight(); got != tt.starHeight {
+				t.Errorf("StarHeight = %d, se esperaba %d", got, tt.starHeight)
+			}
+			if got := n.Depth(); got != tt.depth {
+				t.Errorf("Depth = %d, se esperaba %d", got, tt.depth)
+			}
+		})
+	}
+
+	var empty *Node
+	if empty.Size() != 0 || empty.Width() != 0 || empty.StarHeight() != 0 || empty.Depth() != 0 {
+		t.Errorf("las medidas de un AST nil no son 0")
+	}
+}
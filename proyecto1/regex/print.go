@@ -0,0 +1,86 @@
+package regex
+
+import "strings"
+
+// precedence retorna la precedencia del nodo: unión < concatenación < estrella < literal.
+func (n *Node) precedence() int {
+	switch n.Kind {
+	case Union:
+		return 1
+	case Concat:
+		return 2
+	case Star:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// String retorna la expresión en notación infija, con los paréntesis mínimos necesarios.
+func (n *Node) String() string {
+	var b strings.Builder
+	n.writeInfix(&b, 0)
+	return b.String()
+}
+
+// writeInfix escribe el subárbol, entre paréntesis si su precedencia es menor que la del contexto.
+func (n *Node) writeInfix(b *strings.Builder, ctx int) {
+	if n.precedence() < ctx {
+		b.WriteByte('(')
+		defer b.WriteByte(')')
+	}
+	switch n.Kind {
+	case Literal:
+		b.WriteRune(n.Val)
+	case Union:
+		n.Left.writeInfix(b, 1)
+		b.WriteByte('|')
+		n.Right.writeInfix(b, 1)
+	case Concat:
+		n.Left.writeInfix(b, 2)
+		n.Right.writeInfix(b, 2)
+	case Star:
+		n.Left.writeInfix(b, 3)
+		b.WriteByte('*')
+	}
+}
+
+// label retorna el texto del nodo en el árbol: el símbolo o el operador.
+func (n *Node) label() string {
+	switch n.Kind {
+	case Literal:
+		return string(n.Val)
+	case Union:
+		return "|"
+	case Concat:
+		return "·"
+	default:
+		return "*"
+	}
+}
+
+// Tree retorna el AST dibujado como árbol de texto, un nodo por línea.
+func (n *Node) Tree() string {
+	var b strings.Builder
+	b.WriteString(n.label() + "\n")
+	n.writeChildren(&b, "")
+	return b.String()
+}
+
+// writeChildren escribe los hijos del nodo con las ramas ├── y └──.
+func (n *Node) writeChildren(b *strings.Builder, prefix string) {
+	var children []*Node
+	for _, c := range []*Node{n.Left, n.Right} {
+		if c != nil {
+			children = append(children, c)
+		}
+	}
+	for i, c := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		b.WriteString(prefix + branch + c.label() + "\n")
+		c.writeChildren(b, prefix+indent)
+	}
+}
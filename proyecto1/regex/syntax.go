@@ -0,0 +1,227 @@
+package regex
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+
+	"proyecto1/config"
+)
+
+// Syntax es la sintaxis de una regex de entrada.
+type Syntax int
+
+const (
+	NativeSyntax Syntax = iota // La del proyecto: símbolos, ε, |, ., *, +, ? y paréntesis
+	PerlSyntax                 // La de regexp de Go (estilo Perl/RE2)
+	POSIXSyntax                // POSIX ERE, como regexp.CompilePOSIX
+)
+
+// ParseSyntax interpreta el nombre de una sintaxis: proyecto, perl o posix.
+func ParseSyntax(name string) (Syntax, error) {
+	switch strings.ToLower(name) {
+	case "proyecto", "":
+		return NativeSyntax, nil
+	case "perl":
+		return PerlSyntax, nil
+	case "posix":
+		return POSIXSyntax, nil
+	}
+	return 0, fmt.Errorf("sintaxis desconocida %q (proyecto, perl o posix)", name)
+}
+
+// maxClassSize es la cantidad máxima de símbolos de una clase de caracteres importada: cada símbolo se
+// convierte en una rama de una unión, así que clases como [^a] o . no se pueden representar.
+const maxClassSize = 256
+
+// Import interpreta una regex en sintaxis Perl o POSIX con regexp/syntax, la simplifica (las repeticiones
+// acotadas como x{2,4} se expanden) y la convierte en un AST del proyecto. Se admiten literales, clases de
+// caracteres, unión, concatenación, *, +, ?, repeticiones acotadas, grupos, (?i) y las anclas ^ y $ en
+// los extremos (las cadenas se evalúan completas). Las construcciones sin equivalente regular en el
+// proyecto (referencias hacia atrás, lookarounds, límites de palabra, '.') producen un error que las nombra.
+func Import(pattern string, s Syntax) (*Node, error) {
+	if err := unsupportedPerl(pattern); err != nil {
+		return nil, err
+	}
+	flags := syntax.Perl
+	if s == POSIXSyntax {
+		flags = syntax.POSIX
+	}
+	re, err := syntax.Parse(pattern, flags)
+	if err != nil {
+		return nil, fmt.Errorf("error de sintaxis: %v", err)
+	}
+	re = re.Simplify()
+	return importNode(stripAnchors(re))
+}
+
+// unsupportedPerl reconoce las construcciones de Perl que regexp/syntax rechaza con un error genérico,
+// para explicar por qué no se admiten.
+func unsupportedPerl(pattern string) error {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			if c := pattern[i+1]; c >= '1' && c <= '9' {
+				return fmt.Errorf("referencia hacia atrás \\%c no soportada: no describe un lenguaje regular", c)
+			}
+			i++
+			continue
+		}
+		for _, look := range []string{"(?=", "(?!", "(?<=", "(?<!"} {
+			if strings.HasPrefix(pattern[i:], look) {
+				return fmt.Errorf("lookaround %s...) no soportado", look)
+			}
+		}
+	}
+	return nil
+}
+
+// stripAnchors quita ^ al principio y $ al final de la regex, que no cambian el lenguaje porque las cadenas
+// se evalúan completas.
+func stripAnchors(re *syntax.Regexp) *syntax.Regexp {
+	isBegin := func(r *syntax.Regexp) bool { return r.Op == syntax.OpBeginText || r.Op == syntax.OpBeginLine }
+	isEnd := func(r *syntax.Regexp) bool { return r.Op == syntax.OpEndText || r.Op == syntax.OpEndLine }
+	switch {
+	case isBegin(re) || isEnd(re):
+		return &syntax.Regexp{Op: syntax.OpEmptyMatch}
+	case re.Op == syntax.OpConcat:
+		sub := re.Sub
+		if len(sub) > 0 && isBegin(sub[0]) {
+			sub = sub[1:]
+		}
+		if len(sub) > 0 && isEnd(sub[len(sub)-1]) {
+			sub = sub[:len(sub)-1]
+		}
+		out := *re
+		out.Sub = sub
+		return &out
+	}
+	return re
+}
+
+// epsilon retorna un nodo para la cadena vacía.
+func epsilon() *Node { return &Node{Kind: Literal, Val: 'ε'} }
+
+// foldNodes combina los nodos con el operador binario kind, asociando por la derecha; sin nodos, ε.
+func foldNodes(kind Kind, nodes []*Node) *Node {
+	if len(nodes) == 0 {
+		return epsilon()
+	}
+	n := nodes[len(nodes)-1]
+	for i := len(nodes) - 2; i >= 0; i-- {
+		n = &Node{Kind: kind, Left: nodes[i], Right: n}
+	}
+	return n
+}
+
+// symbol retorna el literal para r, o un error si r no es un símbolo válido en el proyecto.
+func symbol(r rune) (*Node, error) {
+	if r == 'ε' || !config.IsAlphanumeric(r) {
+		return nil, fmt.Errorf("símbolo %q no soportado: el proyecto solo admite letras y dígitos", r)
+	}
+	return &Node{Kind: Literal, Val: r}, nil
+}
+
+// importNode convierte un nodo de regexp/syntax ya simplificado.
+func importNode(re *syntax.Regexp) (*Node, error) {
+	subs := func() ([]*Node, error) {
+		nodes := make([]*Node, 0, len(re.Sub))
+		for _, sub := range re.Sub {
+			n, err := importNode(sub)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		}
+		return nodes, nil
+	}
+
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return epsilon(), nil
+	case syntax.OpLiteral:
+		nodes := make([]*Node, 0, len(re.Rune))
+		for _, r := range re.Rune {
+			variants := []rune{r}
+			if re.Flags&syntax.FoldCase != 0 {
+				for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+					variants = append(variants, f)
+				}
+			}
+			alts := make([]*Node, 0, len(variants))
+			for _, v := range variants {
+				n, err := symbol(v)
+				if err != nil {
+					return nil, err
+				}
+				alts = append(alts, n)
+			}
+			nodes = append(nodes, foldNodes(Union, alts))
+		}
+		return foldNodes(Concat, nodes), nil
+	case syntax.OpCharClass:
+		size := 0
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			size += int(re.Rune[i+1]-re.Rune[i]) + 1
+		}
+		if size == 0 {
+			return nil, fmt.Errorf("la clase %s no contiene símbolos: el proyecto no representa el lenguaje vacío", re)
+		}
+		if size > maxClassSize {
+			return nil, fmt.Errorf("la clase %s tiene %d símbolos (máximo %d); las clases negadas no se pueden representar", re, size, maxClassSize)
+		}
+		alts := make([]*Node, 0, size)
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				n, err := symbol(r)
+				if err != nil {
+					return nil, err
+				}
+				alts = append(alts, n)
+			}
+		}
+		return foldNodes(Union, alts), nil
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return nil, fmt.Errorf("'.' (cualquier carácter) no soportado: use una clase explícita como [ab]")
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return nil, fmt.Errorf("las anclas ^ y $ solo se admiten al principio y al final de la regex")
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return nil, fmt.Errorf("límites de palabra (\\b, \\B) no soportados")
+	case syntax.OpNoMatch:
+		return nil, fmt.Errorf("la regex no acepta ninguna cadena: el proyecto no representa el lenguaje vacío")
+	case syntax.OpCapture:
+		return importNode(re.Sub[0])
+	case syntax.OpConcat, syntax.OpAlternate:
+		nodes, err := subs()
+		if err != nil {
+			return nil, err
+		}
+		if re.Op == syntax.OpConcat {
+			return foldNodes(Concat, nodes), nil
+		}
+		return foldNodes(Union, nodes), nil
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest:
+		x, err := importNode(re.Sub[0])
+		if err != nil {
+			return nil, err
+		}
+		switch re.Op {
+		case syntax.OpStar:
+			return &Node{Kind: Star, Left: x}, nil
+		case syntax.OpPlus:
+			return &Node{Kind: Concat, Left: x, Right: &Node{Kind: Star, Left: x.clone()}}, nil
+		}
+		return &Node{Kind: Union, Left: x, Right: epsilon()}, nil
+	}
+	return nil, fmt.Errorf("construcción %s no soportada", re)
+}
+
+// clone retorna una copia profunda del subárbol.
+func (n *Node) clone() *Node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.Left, c.Right = n.Left.clone(), n.Right.clone()
+	return &c
+}
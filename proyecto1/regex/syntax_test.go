@@ -0,0 +1,82 @@
+package regex
+
+import (
+	"strings"
+	"testing"
+
+	"proyecto1/config"
+)
+
+// parse construye el AST de una regex del proyecto igual que el pipeline.
+func parse(t *testing.T, r string) *Node {
+	t.Helper()
+	n, err := BuildAST(config.InfixToPostfix(config.FormatRegex(config.ExpandRegexExtensions(r))))
+	if err != nil {
+		t.Fatalf("%s: %v", r, err)
+	}
+	return n
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		pattern string
+		syntax  Syntax
+		want    string // String del AST importado
+	}{
+		{"a{2}", PerlSyntax, "aa"},
+		{"a{1,3}b", PerlSyntax, "a(a(a|ε)|ε)b"},
+		{"[ab]c?", PerlSyntax, "(a|b)(c|ε)"},
+		{"(?i)a", PerlSyntax, "A|a"},
+		{"^ab$", PerlSyntax, "ab"},
+		{"a+|b", PerlSyntax, "aa*|b"},
+		{"[ab]", POSIXSyntax, "a|b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			n, err := Import(tt.pattern, tt.syntax)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := n.String()
+			if got != tt.want {
+				t.Errorf("Import = %q, se esperaba %q", got, tt.want)
+			}
+			// El texto impreso se vuelve a leer como regex del proyecto sin cambios
+			if again := parse(t, got).String(); again != got {
+				t.Errorf("%q se relee como %q", got, again)
+			}
+		})
+	}
+}
+
+func TestImportUnsupported(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string // Fragmento esperado del mensaje de error
+	}{
+		{`(a|b)\1`, "referencia hacia atrás"},
+		{"a.b", "'.'"},
+		{`\bfoo`, "límites de palabra"},
+		{"(?=a)", "lookaround"},
+		{"a(", "error de sintaxis"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := Import(tt.pattern, PerlSyntax)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %v, se esperaba uno que mencione %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSyntax(t *testing.T) {
+	for name, want := range map[string]Syntax{"": NativeSyntax, "proyecto": NativeSyntax, "Perl": PerlSyntax, "posix": POSIXSyntax} {
+		if got, err := ParseSyntax(name); err != nil || got != want {
+			t.Errorf("ParseSyntax(%q) = %v, %v; se esperaba %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseSyntax("pcre"); err == nil {
+		t.Errorf("ParseSyntax aceptó una sintaxis desconocida")
+	}
+}
@@ -0,0 +1,310 @@
+// /proyecto1/repl.go
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"proyecto1/config"
+	"proyecto1/graphviz"
+	"proyecto1/nfa"
+)
+
+// replSession es el estado de la consola interactiva: la regex compilada actual y sus opciones.
+type replSession struct {
+	out    io.Writer
+	cur    *compiled
+	sigma  []rune // Alfabeto declarado con :sigma (nil = símbolos de la regex)
+	minAlg nfa.MinimizeAlgorithm
+}
+
+// replCommand es un comando de la consola (:nombre argumento).
+type replCommand struct {
+	name, usage, help string
+	run               func(s *replSession, arg string) error
+}
+
+// replCommands lista los comandos en el orden en que los muestra :help.
+var replCommands []replCommand
+
+func init() {
+	replCommands = []replCommand{
+		{"regex", ":regex <regex>", "compila la regex (NFA, DFA y DFA minimizado) y la deja como actual", (*replSession).cmdRegex},
+		{"sigma", ":sigma [a,b,...]", "declara el alfabeto (sin argumento vuelve a derivarlo de la regex)", (*replSession).cmdSigma},
+		{"match", ":match <w1,w2,...>", "evalúa las cadenas en el NFA, el DFA y el DFA minimizado", (*replSession).cmdMatch},
+		{"info", ":info", "muestra las etapas de la regex actual y la cantidad de estados", (*replSession).cmdInfo},
+		{"nfa", ":nfa", "muestra la tabla de transiciones del NFA", (*replSession).cmdNFA},
+		{"dfa", ":dfa", "muestra la tabla de transiciones del DFA", (*replSession).cmdDFA},
+		{"min", ":min [algoritmo]", "muestra el DFA minimizado (opcionalmente con otro algoritmo: " + minimizeAlgorithmNames + ")", (*replSession).cmdMin},
+		{"trace", ":trace <w>", "muestra paso a paso los estados activos del NFA y del DFA al leer w", (*replSession).cmdTrace},
+		{"equiv", ":equiv <regex>", "compara el lenguaje actual con el de otra regex", (*replSession).cmdEquiv},
+		{"save", ":save <archivo> [nfa|dfa|min]", "guarda un autómata (por defecto el minimizado) según la extensión: .json, .jff, .svg, .png, .dot, .mmd, .tex o .md", (*replSession).cmdSave},
+		{"help", ":help", "muestra esta ayuda", (*replSession).cmdHelp},
+		{"quit", ":quit", "sale de la consola (también :q o fin de archivo)", nil},
+	}
+}
+
+// minimizeAlgorithmNames son los valores aceptados por nfa.ParseMinimizeAlgorithm.
+const minimizeAlgorithmNames = "hopcroft, moore, brzozowski o check"
+
+// runREPL implementa el subcomando repl: lee comandos de la entrada estándar hasta :quit o EOF.
+func runREPL(args []string) error {
+	fs := flag.NewFlagSet("repl", flag.ExitOnError)
+	minName := fs.String("min", "hopcroft", "algoritmo de minimización: "+minimizeAlgorithmNames)
+	sigmaSpec := fs.String("sigma", "", "alfabeto inicial de la sesión")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	s := &replSession{out: os.Stdout}
+	var err error
+	if s.minAlg, err = nfa.ParseMinimizeAlgorithm(*minName); err != nil {
+		return err
+	}
+	if s.sigma, err = parseSigma(*sigmaSpec); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(s.out, "Consola de autómatas. Escribe :help para ver los comandos.")
+	sc := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Fprint(s.out, "> ")
+		if !sc.Scan() {
+			fmt.Fprintln(s.out)
+			return sc.Err()
+		}
+		if s.exec(sc.Text()) {
+			return nil
+		}
+	}
+}
+
+// exec ejecuta una línea de la consola. Retorna true si la sesión debe terminar.
+func (s *replSession) exec(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false
+	}
+	if !strings.HasPrefix(line, ":") {
+		fmt.Fprintln(s.out, "Los comandos empiezan con ':' (por ejemplo :regex (a|b)*abb). Escribe :help para ver la lista.")
+		return false
+	}
+	name, arg, _ := strings.Cut(line[1:], " ")
+	arg = strings.TrimSpace(arg)
+	if name == "quit" || name == "q" || name == "exit" {
+		return true
+	}
+	for _, cmd := range replCommands {
+		if cmd.name == name && cmd.run != nil {
+			if err := cmd.run(s, arg); err != nil {
+				fmt.Fprintf(s.out, "Error: %v\n", err)
+			}
+			return false
+		}
+	}
+	fmt.Fprintf(s.out, "Comando desconocido :%s. Escribe :help para ver la lista.\n", name)
+	return false
+}
+
+// current retorna la regex compilada actual o un error si todavía no hay ninguna.
+func (s *replSession) current() (*compiled, error) {
+	if s.cur == nil {
+		return nil, fmt.Errorf("no hay una regex actual; usa :regex <regex>")
+	}
+	return s.cur, nil
+}
+
+// compile compila la regex con el alfabeto y el algoritmo de la sesión.
+func (s *replSession) compile(r string) (*compiled, error) {
+	if r == "" {
+		return nil, fmt.Errorf("falta la regex")
+	}
+	return compilePipeline(r, s.sigma, s.minAlg)
+}
+
+func (s *replSession) cmdRegex(arg string) error {
+	c, err := s.compile(arg)
+	if err != nil {
+		return err
+	}
+	s.cur = c
+	return s.cmdInfo("")
+}
+
+func (s *replSession) cmdSigma(arg string) error {
+	sigma, err := parseSigma(arg)
+	if err != nil {
+		return err
+	}
+	s.sigma = sigma
+	if sigma == nil {
+		fmt.Fprintln(s.out, "Alfabeto: se deriva de cada regex")
+	} else {
+		fmt.Fprintf(s.out, "Alfabeto: %s\n", config.FormatAlphabet(sigma))
+	}
+	// Recompilar la regex actual sobre el nuevo alfabeto
+	if s.cur != nil {
+		return s.cmdRegex(s.cur.Original)
+	}
+	return nil
+}
+
+func (s *replSession) cmdMatch(arg string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	if arg == "" {
+		return fmt.Errorf("faltan las cadenas a evaluar")
+	}
+	for _, tok := range strings.Split(arg, ",") {
+		res, err := c.match(config.ParseWord(strings.TrimSpace(tok)), s.sigma != nil)
+		if err != nil {
+			fmt.Fprintf(s.out, "  %q: error: %v\n", res.Word, err)
+			continue
+		}
+		fmt.Fprintf(s.out, "  %q: NFA %s%s, DFA %s, minDFA %s\n", res.Word, yesNo(res.NFA), setsNote(res.NFA, res.Sets), yesNo(res.DFA), yesNo(res.Min))
+	}
+	return nil
+}
+
+func (s *replSession) cmdInfo(string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "  Regex original: %s\n", c.Original)
+	fmt.Fprintf(s.out, "  Expandida: %s\n", c.Expanded)
+	fmt.Fprintf(s.out, "  Formateada: %s\n", c.Formatted)
+	fmt.Fprintf(s.out, "  Postfija: %s\n", c.Postfix)
+	fmt.Fprintf(s.out, "  Alfabeto: %s\n", config.FormatAlphabet(c.Alphabet))
+	fmt.Fprintf(s.out, "  Estados: NFA %d, DFA %d, DFA minimizado %d\n", len(c.NFA.States), len(c.DFA.States), len(c.MinDFA.States))
+	return nil
+}
+
+func (s *replSession) cmdNFA(string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	return graphviz.FormatTable(s.out, c.NFA)
+}
+
+func (s *replSession) cmdDFA(string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	return graphviz.FormatTableDFA(s.out, c.DFA)
+}
+
+func (s *replSession) cmdMin(arg string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	if arg != "" {
+		alg, err := nfa.ParseMinimizeAlgorithm(arg)
+		if err != nil {
+			return err
+		}
+		// El algoritmo cambia solo si se pudo volver a minimizar con él
+		next, err := compilePipeline(c.Original, s.sigma, alg)
+		if err != nil {
+			return err
+		}
+		s.minAlg, s.cur, c = alg, next, next
+		fmt.Fprintf(s.out, "Algoritmo de minimización: %v\n", alg)
+	}
+	return graphviz.FormatTableDFA(s.out, c.MinDFA)
+}
+
+func (s *replSession) cmdTrace(arg string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	w := config.ParseWord(arg)
+	if s.sigma != nil {
+		if err := config.ValidateWord(w, c.Alphabet); err != nil {
+			return err
+		}
+	}
+	nfaSteps, nfaOK := nfa.TraceNFA(c.NFA, w)
+	dfaSteps, dfaOK := nfa.TraceDFA(c.MinDFA, w)
+	fmt.Fprintf(s.out, "  Traza de %q (DFA minimizado y NFA):\n", w)
+	for i := range nfaSteps {
+		read := "inicio"
+		if i > 0 {
+			read = fmt.Sprintf("lee %c", nfaSteps[i].Symbol)
+		}
+		fmt.Fprintf(s.out, "  %-8s minDFA %-8s NFA %s\n", read, formatStates(dfaSteps[i].States), formatStates(nfaSteps[i].States))
+	}
+	fmt.Fprintf(s.out, "  Veredicto: minDFA %s, NFA %s\n", yesNo(dfaOK), yesNo(nfaOK))
+	return nil
+}
+
+// formatStates escribe un conjunto de estados como {q0, q1}, o ∅ si está vacío.
+func formatStates(states []string) string {
+	if len(states) == 0 {
+		return "∅"
+	}
+	return "{" + strings.Join(states, ", ") + "}"
+}
+
+func (s *replSession) cmdEquiv(arg string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	other, err := s.compile(arg)
+	if err != nil {
+		return err
+	}
+	alphabet := append(append([]rune{}, c.Alphabet...), other.Alphabet...)
+	equivalent, witness := nfa.Equivalent(c.MinDFA, other.MinDFA, alphabet)
+	if equivalent {
+		fmt.Fprintf(s.out, "  %s y %s son equivalentes\n", c.Original, other.Original)
+		return nil
+	}
+	in, out := c.Original, other.Original
+	if !nfa.SimulateDFA(c.MinDFA, witness) {
+		in, out = out, in
+	}
+	fmt.Fprintf(s.out, "  No son equivalentes: %q pertenece a L(%s) pero no a L(%s)\n", witness, in, out)
+	return nil
+}
+
+func (s *replSession) cmdSave(arg string) error {
+	c, err := s.current()
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(arg)
+	if len(fields) == 0 || len(fields) > 2 {
+		return fmt.Errorf("uso: :save <archivo> [nfa|dfa|min]")
+	}
+	path, stage := fields[0], "min"
+	if len(fields) == 2 {
+		stage = fields[1]
+	}
+	a, err := c.stage(stage)
+	if err != nil {
+		return err
+	}
+	if err := saveAutomaton(a, nil, path); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "  Guardado: %s\n", path)
+	return nil
+}
+
+func (s *replSession) cmdHelp(string) error {
+	for _, cmd := range replCommands {
+		fmt.Fprintf(s.out, "  %-32s %s\n", cmd.usage, cmd.help)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// /proyecto1/report.go
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+
+	"proyecto1/config"
+	"proyecto1/graphviz"
+	"proyecto1/nfa"
+	"proyecto1/regex"
+	"proyecto1/thompson"
+)
+
+// reportLine reúne lo calculado para una línea de la entrada, para el reporte HTML.
+type reportLine struct {
+	LineNo                                 int
+	Original, Expanded, Formatted, Postfix string
+	AST                                    *regex.Node
+	Alphabet                               []rune
+	NFA                                    *thompson.NFA
+	DFA, MinDFA                            *nfa.DFA
+	Words                                  []reportWord
+	Metrics                                *Metrics // Nil si la línea no llegó a los autómatas
+	Err                                    string   // Error que detuvo el procesamiento de la línea
+}
+
+// reportWord es el veredicto de cada autómata para una cadena (o el error de validación).
+type reportWord struct {
+	Word          string
+	Err           string
+	NFA, DFA, Min bool
+	Sets          bool               // El NFA simulado por conjuntos (nfa.Simulate); NFA es el de nfa.NewSimulator
+	Expect        config.Expectation // Veredicto esperado (+w / -w en la entrada)
+}
+
+// Agree indica si los autómatas (y las dos simulaciones del NFA) coinciden en la cadena.
+func (w reportWord) Agree() bool {
+	return w.NFA == w.Sets && w.NFA == w.DFA && w.DFA == w.Min
+}
+
+// reportSection son los datos que la plantilla muestra para una línea.
+type reportSection struct {
+	*reportLine
+	Tree                         string
+	Sigma                        string
+	NFASVG, DFASVG, MinDFASVG    template.HTML
+	NFAStates, DFAStates         int
+	MinStates, NFATransitions    int
+	HasAutomata, HasDisagreement bool
+	HasExpectations, HasFailures bool // Cadenas con veredicto esperado (+w / -w) y si alguna falla
+}
+
+// reportTemplate es un documento HTML autocontenido: estilos en línea y SVGs incrustados.
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"yesNo": yesNo,
+	"inc":   func(i int) int { return i + 1 },
+	"word":  config.FormatWord,
+	"expected": func(w reportWord) string {
+		if w.Expect == config.NoExpectation {
+			return "—"
+		}
+		return yesNo(w.Expect.Accepts())
+	},
+	"failed": func(w reportWord) bool {
+		return w.Expect != config.NoExpectation && !checkWord(w, w.Expect)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Reporte: {{.Input}}</title>
+<style>
+  body { font-family: sans-serif; margin: 2em auto; max-width: 70em; color: #222; }
+  section { border-top: 1px solid #ccc; padding: 1em 0; }
+  table { border-collapse: collapse; margin: .5em 0; }
+  th, td { border: 1px solid #ccc; padding: .25em .75em; text-align: left; }
+  th { background: #f3f3f3; }
+  code, pre { font-family: monospace; background: #f7f7f7; }
+  pre { padding: .5em; display: inline-block; margin: .25em 0; }
+  .error { color: #b00020; }
+  .si { color: #1b5e20; font-weight: bold; }
+  .no { color: #888; }
+  .diff { background: #fff3e0; }
+  .fail { background: #fde7e9; }
+  .automata { display: flex; flex-wrap: wrap; gap: 1em; }
+  figure { margin: 0; border: 1px solid #eee; padding: .5em; overflow-x: auto; max-width: 100%; }
+  figcaption { font-weight: bold; margin-bottom: .25em; }
+</style>
+</head>
+<body>
+<h1>Reporte de <code>{{.Input}}</code></h1>
+<p>Generado el {{.Generated}}. Líneas procesadas: {{len .Sections}}.</p>
+<nav><ul>
+{{- range .Sections}}
+  <li><a href="#linea-{{.LineNo}}">Línea {{.LineNo}}</a>: <code>{{.Original}}</code>{{if .Err}} <span class="error">(error)</span>{{end}}</li>
+{{- end}}
+</ul></nav>
+{{range .Sections}}
+<section id="linea-{{.LineNo}}">
+<h2>Línea {{.LineNo}}: <code>{{.Original}}</code></h2>
+<table>
+  <tr><th>Original</th><td><code>{{.Original}}</code></td></tr>
+  <tr><th>Expandida</th><td><code>{{.Expanded}}</code></td></tr>
+  <tr><th>Formateada</th><td><code>{{.Formatted}}</code></td></tr>
+  <tr><th>Postfija</th><td><code>{{.Postfix}}</code></td></tr>
+  {{- if .Sigma}}
+  <tr><th>Alfabeto</th><td><code>{{.Sigma}}</code></td></tr>
+  {{- end}}
+</table>
+{{- if .Tree}}
+<h3>AST</h3>
+<pre>{{.Tree}}</pre>
+{{- end}}
+{{- if .Err}}
+<p class="error">Error: {{.Err}}</p>
+{{- end}}
+{{- if .HasAutomata}}
+<h3>Autómatas</h3>
+<table>
+  <tr><th></th><th>Estados</th></tr>
+  <tr><td>NFA (Thompson)</td><td>{{.NFAStates}} ({{.NFATransitions}} transiciones)</td></tr>
+  <tr><td>DFA</td><td>{{.DFAStates}}</td></tr>
+  <tr><td>DFA minimizado</td><td>{{.MinStates}}</td></tr>
+</table>
+<div class="automata">
+  <figure><figcaption>NFA</figcaption>{{.NFASVG}}</figure>
+  <figure><figcaption>DFA</figcaption>{{.DFASVG}}</figure>
+  <figure><figcaption>DFA minimizado</figcaption>{{.MinDFASVG}}</figure>
+</div>
+{{- end}}
+{{- if .Words}}
+<h3>Cadenas</h3>
+<table>
+  {{- $sec := .}}
+  <tr><th>#</th><th>w</th><th>NFA</th><th>DFA</th><th>minDFA</th>{{if .HasExpectations}}<th>Esperado</th>{{end}}</tr>
+  {{- range $i, $w := .Words}}
+  {{- if $w.Err}}
+  <tr{{if failed $w}} class="fail"{{end}}><td>{{$i | inc}}</td><td><code>{{word $w.Word}}</code></td><td colspan="3" class="error">{{$w.Err}}</td>
+  {{- else}}
+  <tr{{if failed $w}} class="fail"{{else if not $w.Agree}} class="diff"{{end}}><td>{{$i | inc}}</td><td><code>{{word $w.Word}}</code></td>
+    <td class="{{if $w.NFA}}si{{else}}no{{end}}">{{yesNo $w.NFA}}</td>
+    <td class="{{if $w.DFA}}si{{else}}no{{end}}">{{yesNo $w.DFA}}</td>
+    <td class="{{if $w.Min}}si{{else}}no{{end}}">{{yesNo $w.Min}}</td>
+  {{- end}}
+  {{- if $sec.HasExpectations}}<td>{{expected $w}}</td>{{end}}</tr>
+  {{- end}}
+</table>
+{{- if .HasDisagreement}}
+<p class="error">Los autómatas no coinciden en las filas resaltadas.</p>
+{{- end}}
+{{- if .HasFailures}}
+<p class="error">Las filas en rojo no coinciden con el veredicto esperado.</p>
+{{- end}}
+{{- end}}
+</section>
+{{end}}
+</body>
+</html>
+`))
+
+// writeHTMLReport escribe en path un reporte HTML autocontenido con una sección por línea de la entrada.
+func writeHTMLReport(path, inPath string, lines []*reportLine) error {
+	sections := make([]reportSection, 0, len(lines))
+	for _, line := range lines {
+		sec := reportSection{reportLine: line}
+		if line.AST != nil {
+			sec.Tree = line.AST.Tree()
+		}
+		if line.Alphabet != nil {
+			sec.Sigma = config.FormatAlphabet(line.Alphabet)
+		}
+		if line.NFA != nil && line.DFA != nil && line.MinDFA != nil {
+			sec.HasAutomata = true
+			sec.NFAStates = len(line.NFA.States)
+			for _, s := range line.NFA.States {
+				for _, targets := range s.Trans {
+					sec.NFATransitions += len(targets)
+				}
+			}
+			sec.DFAStates = len(line.DFA.States)
+			sec.MinStates = len(line.MinDFA.States)
+			var buf bytes.Buffer
+			if err := graphviz.RenderSVG(&buf, line.NFA); err != nil {
+				return err
+			}
+			sec.NFASVG = template.HTML(buf.String()) // Generado por graphviz.RenderSVG, con los textos escapados
+			buf.Reset()
+			if err := graphviz.RenderSVGDFA(&buf, line.DFA); err != nil {
+				return err
+			}
+			sec.DFASVG = template.HTML(buf.String())
+			buf.Reset()
+			if err := graphviz.RenderSVGDFA(&buf, line.MinDFA); err != nil {
+				return err
+			}
+			sec.MinDFASVG = template.HTML(buf.String())
+		}
+		for _, w := range line.Words {
+			if w.Err == "" && !w.Agree() {
+				sec.HasDisagreement = true
+			}
+			if w.Expect != config.NoExpectation {
+				sec.HasExpectations = true
+				sec.HasFailures = sec.HasFailures || !checkWord(w, w.Expect)
+			}
+		}
+		sections = append(sections, sec)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = reportTemplate.Execute(f, struct {
+		Input, Generated string
+		Sections         []reportSection
+	}{filepath.Base(inPath), time.Now().Format("2006-01-02 15:04"), sections})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
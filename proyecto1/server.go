@@ -0,0 +1,291 @@
+// /proyecto1/server.go
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"proyecto1/config"
+	"proyecto1/nfa"
+)
+
+// playgroundHTML es la página del playground: formulario y JavaScript que llaman a la API JSON.
+//
+//go:embed web/playground.html
+var playgroundHTML []byte
+
+// maxRequestBytes limita el tamaño del cuerpo de las peticiones a la API.
+const maxRequestBytes = 1 << 20
+
+// compileRequest es el cuerpo de /compile, /match y /minimize (con regex).
+type compileRequest struct {
+	Regex     string             `json:"regex"`
+	Sigma     string             `json:"sigma,omitempty"`     // Alfabeto declarado, por ejemplo "a,b,c"
+	Algorithm string             `json:"algorithm,omitempty"` // Algoritmo de minimización (-min)
+	Words     []string           `json:"words,omitempty"`     // Cadenas para /match ("ε" es la cadena vacía)
+	Automaton *nfa.JSONAutomaton `json:"automaton,omitempty"` // Autómata para /minimize en lugar de regex
+}
+
+// equivalentRequest es el cuerpo de /equivalent.
+type equivalentRequest struct {
+	Regex1 string `json:"regex1"`
+	Regex2 string `json:"regex2"`
+	Sigma  string `json:"sigma,omitempty"`
+}
+
+// svgSet son los dibujos de las tres etapas.
+type svgSet struct {
+	NFA    string `json:"nfa,omitempty"`
+	DFA    string `json:"dfa,omitempty"`
+	MinDFA string `json:"min_dfa"`
+}
+
+// compileResponse es la respuesta de /compile.
+type compileResponse struct {
+	Original  string             `json:"original"`
+	Expanded  string             `json:"expanded"`
+	Formatted string             `json:"formatted"`
+	Postfix   string             `json:"postfix"`
+	AST       string             `json:"ast"`
+	ASTTree   string             `json:"ast_tree"`
+	Alphabet  []string           `json:"alphabet"`
+	NFA       *nfa.JSONAutomaton `json:"nfa"`
+	DFA       *nfa.JSONAutomaton `json:"dfa"`
+	MinDFA    *nfa.JSONAutomaton `json:"min_dfa"`
+	Metrics   Metrics            `json:"metrics"`
+	SVG       *svgSet            `json:"svg,omitempty"`
+}
+
+// matchResult es el veredicto de cada autómata para una cadena en /match.
+type matchResult struct {
+	Word    string `json:"word"`
+	NFA     bool   `json:"nfa"`
+	NFASets bool   `json:"nfa_sets"` // La simulación del NFA por conjuntos, que debe coincidir con nfa
+	DFA     bool   `json:"dfa"`
+	MinDFA  bool   `json:"min_dfa"`
+	Error   string `json:"error,omitempty"`
+}
+
+// newMatchResult convierte el resultado de compiled.match.
+func newMatchResult(res reportWord) matchResult {
+	return matchResult{Word: res.Word, NFA: res.NFA, NFASets: res.Sets, DFA: res.DFA, MinDFA: res.Min, Error: res.Err}
+}
+
+// runServe implementa el subcomando serve: un servidor HTTP local con el playground y la API JSON.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "dirección en la que escucha el servidor")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	log.Printf("Playground en http://%s/ (API: /compile, /match, /equivalent, /minimize)", *addr)
+	return http.ListenAndServe(*addr, newServeMux())
+}
+
+// newServeMux registra la página del playground y los endpoints de la API.
+func newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(playgroundHTML)
+	})
+	mux.HandleFunc("/compile", jsonHandler(handleCompile))
+	mux.HandleFunc("/match", jsonHandler(handleMatch))
+	mux.HandleFunc("/equivalent", jsonHandler(handleEquivalent))
+	mux.HandleFunc("/minimize", jsonHandler(handleMinimize))
+	return mux
+}
+
+// jsonHandler adapta un manejador de la API: acepta solo POST con cuerpo JSON, responde el valor
+// retornado como JSON y los errores como {"error": "..."} con estado 400.
+func jsonHandler[T any](handle func(req *T) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "se esperaba POST con un cuerpo JSON"})
+			return
+		}
+		req := new(T)
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("JSON inválido: %v", err)})
+			return
+		}
+		resp, err := handle(req)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// writeJSON escribe v como JSON con el estado indicado.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// parseSigma interpreta el alfabeto opcional de una petición (nil si no se declaró).
+func parseSigma(spec string) ([]rune, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	sigma, err := config.ParseAlphabet(spec)
+	if err != nil {
+		return nil, fmt.Errorf("alfabeto inválido: %v", err)
+	}
+	return sigma, nil
+}
+
+// compileFromRequest compila la regex de la petición con su alfabeto y algoritmo de minimización.
+func compileFromRequest(req *compileRequest) (*compiled, []rune, error) {
+	if strings.TrimSpace(req.Regex) == "" {
+		return nil, nil, errors.New("falta el campo regex")
+	}
+	sigma, err := parseSigma(req.Sigma)
+	if err != nil {
+		return nil, nil, err
+	}
+	alg, err := nfa.ParseMinimizeAlgorithm(req.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := compilePipeline(strings.TrimSpace(req.Regex), sigma, alg)
+	return c, sigma, err
+}
+
+// renderSVGString dibuja un autómata con el renderizador SVG nativo.
+func renderSVGString(a nfa.Automaton) string {
+	var buf bytes.Buffer
+	renderSVGTo(&buf, a)
+	return buf.String()
+}
+
+// newCompileResponse describe las etapas del pipeline con los autómatas en JSON, sin los dibujos.
+func newCompileResponse(c *compiled) *compileResponse {
+	resp := &compileResponse{
+		Original:  c.Original,
+		Expanded:  c.Expanded,
+		Formatted: c.Formatted,
+		Postfix:   c.Postfix,
+		Alphabet:  []string{},
+		NFA:       nfa.NFAToJSON(c.NFA),
+		DFA:       nfa.DFAToJSON(c.DFA),
+		MinDFA:    nfa.DFAToJSON(c.MinDFA),
+		Metrics:   c.Metrics(),
+	}
+	if c.AST != nil {
+		resp.AST = c.AST.String()
+		resp.ASTTree = c.AST.Tree()
+	}
+	for _, sym := range c.Alphabet {
+		resp.Alphabet = append(resp.Alphabet, string(sym))
+	}
+	return resp
+}
+
+// handleCompile responde todas las etapas del pipeline para una regex, con los autómatas en JSON y SVG.
+func handleCompile(req *compileRequest) (any, error) {
+	c, _, err := compileFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp := newCompileResponse(c)
+	resp.SVG = &svgSet{
+		NFA:    renderSVGString(c.NFA),
+		DFA:    renderSVGString(c.DFA),
+		MinDFA: renderSVGString(c.MinDFA),
+	}
+	return resp, nil
+}
+
+// handleMatch evalúa cada cadena de la petición en el NFA, el DFA y el DFA minimizado.
+func handleMatch(req *compileRequest) (any, error) {
+	c, sigma, err := compileFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	results := []matchResult{}
+	for _, tok := range req.Words {
+		res, _ := c.match(config.ParseWord(strings.TrimSpace(tok)), sigma != nil)
+		results = append(results, newMatchResult(res))
+	}
+	return map[string]any{"results": results}, nil
+}
+
+// handleEquivalent compara los lenguajes de dos regex y, si difieren, retorna la cadena testigo
+// más corta y a cuál de los dos lenguajes pertenece.
+func handleEquivalent(req *equivalentRequest) (any, error) {
+	sigma, err := parseSigma(req.Sigma)
+	if err != nil {
+		return nil, err
+	}
+	var automata [2]*compiled
+	for i, r := range []string{req.Regex1, req.Regex2} {
+		if strings.TrimSpace(r) == "" {
+			return nil, fmt.Errorf("falta el campo regex%d", i+1)
+		}
+		if automata[i], err = compilePipeline(strings.TrimSpace(r), sigma, nfa.Hopcroft); err != nil {
+			return nil, fmt.Errorf("regex%d: %v", i+1, err)
+		}
+	}
+	return equivalence(automata[0], automata[1], sigma), nil
+}
+
+// handleMinimize minimiza el DFA de una regex o un autómata dado en JSON (ver docs/automaton.schema.json).
+// Con una regex, el DFA minimizado es el del pipeline (como en la línea de comandos).
+func handleMinimize(req *compileRequest) (any, error) {
+	alg, err := nfa.ParseMinimizeAlgorithm(req.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var dfa, minDFA *nfa.DFA
+	if req.Automaton != nil {
+		var a nfa.Automaton
+		if req.Automaton.Type == nfa.JSONTypeDFA {
+			a, err = req.Automaton.ToDFA()
+		} else {
+			a, err = req.Automaton.ToNFA()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("autómata inválido: %v", err)
+		}
+		if d, ok := a.(*nfa.DFA); ok {
+			dfa = d
+		} else {
+			dfa = nfa.NFAtoDFA(a.AsNFA(), nfa.Alphabet(a))
+		}
+		if minDFA, err = nfa.MinimizeDFAWith(dfa, alg); err != nil {
+			return nil, err
+		}
+	} else {
+		c, _, err := compileFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		dfa, minDFA = c.DFA, c.MinDFA
+	}
+	return map[string]any{
+		"algorithm":     alg.String(),
+		"states_before": len(dfa.States),
+		"states_after":  len(minDFA.States),
+		"min_dfa":       nfa.DFAToJSON(minDFA),
+		"svg":           &svgSet{MinDFA: renderSVGString(minDFA)},
+	}, nil
+}
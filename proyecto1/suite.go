@@ -0,0 +1,243 @@
+// /proyecto1/suite.go
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"proyecto1/config"
+)
+
+// inputLine es una línea regex;w1,w2,...[;Σ] de un archivo de entrada, ya interpretada.
+type inputLine struct {
+	No    int
+	Regex string
+	Words []config.TestWord
+	Sigma []rune // Σ de la línea o de la directiva @alfabeto vigente (nil = derivar de la regex)
+}
+
+// scanInput recorre un archivo en el formato de input.txt: omite las líneas vacías y los comentarios (#),
+// aplica las directivas @alfabeto y llama a visit con cada línea válida, en orden. Las líneas mal formadas
+// se informan con invalid y se omiten.
+func scanInput(r io.Reader, visit func(in inputLine), invalid func(lineNo int, msg string)) error {
+	sc := bufio.NewScanner(r)
+	// Ampliar buffer por si hay líneas largas
+	buf := make([]byte, 0, 1024*1024)
+	sc.Buffer(buf, 1024*1024)
+
+	lineNo := 0
+	var fileSigma []rune // Σ declarado con @alfabeto (nil = derivar de cada regex)
+	for sc.Scan() {
+		lineNo++
+		raw := strings.TrimSpace(sc.Text())
+		if raw == "" || strings.HasPrefix(raw, "#") {
+			continue
+		}
+
+		// @alfabeto a,b,c  → Σ para las líneas siguientes
+		if strings.HasPrefix(raw, config.AlphabetDirective) {
+			spec := strings.TrimSpace(strings.TrimPrefix(raw, config.AlphabetDirective))
+			if spec == "" {
+				fileSigma = nil
+				continue
+			}
+			sigma, err := config.ParseAlphabet(spec)
+			if err != nil {
+				invalid(lineNo, fmt.Sprintf("directiva %s inválida: %v", config.AlphabetDirective, err))
+				continue
+			}
+			fileSigma = sigma
+			continue
+		}
+
+		// regex ; w1,w2,w3 [; Σ]
+		parts := strings.SplitN(raw, ";", 3)
+		if len(parts) < 2 {
+			invalid(lineNo, fmt.Sprintf("formato inválido. Se esperaba 'regex;w1,w2,...[;Σ]'. Se encontró: %q", raw))
+			continue
+		}
+		r := strings.TrimSpace(parts[0])
+		wsCSV := strings.TrimSpace(parts[1])
+		if r == "" {
+			invalid(lineNo, "regex vacía antes de ';'")
+			continue
+		}
+		if wsCSV == "" {
+			invalid(lineNo, "no hay cadenas después de ';'")
+			continue
+		}
+
+		// Σ de la línea (tercer campo) o del archivo
+		sigma := fileSigma
+		if len(parts) == 3 && strings.TrimSpace(parts[2]) != "" {
+			lineSigma, err := config.ParseAlphabet(parts[2])
+			if err != nil {
+				invalid(lineNo, fmt.Sprintf("alfabeto inválido: %v", err))
+				continue
+			}
+			sigma = lineSigma
+		}
+
+		// Lista de cadenas (separadas por coma); "ε" denota la cadena vacía y +w / -w el veredicto esperado
+		words := config.ParseTestWords(wsCSV)
+		if len(words) == 0 {
+			invalid(lineNo, "no hay cadenas válidas para evaluar")
+			continue
+		}
+		visit(inputLine{No: lineNo, Regex: r, Words: words, Sigma: sigma})
+	}
+	return sc.Err()
+}
+
+// suiteCase es el resultado de una cadena con veredicto esperado.
+type suiteCase struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Regex    string `json:"regex"`
+	Word     string `json:"word"`
+	Expected bool   `json:"expected"`
+	NFA      bool   `json:"nfa"`
+	NFASets  bool   `json:"nfa_sets"`
+	DFA      bool   `json:"dfa"`
+	MinDFA   bool   `json:"min_dfa"`
+	Error    string `json:"error,omitempty"`
+	Pass     bool   `json:"pass"`
+}
+
+// suiteSummary cuenta los casos de una o más suites.
+type suiteSummary struct {
+	Cases     int `json:"cases"`
+	Passed    int `json:"passed"`
+	Failed    int `json:"failed"`
+	Unchecked int `json:"unchecked"` // Cadenas sin marca + o -
+}
+
+// add cuenta los casos marcados de una línea con sus resultados, en el mismo orden que words. Si la línea
+// falló antes de evaluar todas sus cadenas (results es más corto), las cadenas sin resultado cuentan como fallas.
+func (s *suiteSummary) add(words []config.TestWord, results []reportWord) {
+	for i, tw := range words {
+		if tw.Expect == config.NoExpectation {
+			s.Unchecked++
+			continue
+		}
+		s.Cases++
+		if i < len(results) && checkWord(results[i], tw.Expect) {
+			s.Passed++
+		} else {
+			s.Failed++
+		}
+	}
+}
+
+// merge suma los casos de otro resumen.
+func (s *suiteSummary) merge(o suiteSummary) {
+	s.Cases += o.Cases
+	s.Passed += o.Passed
+	s.Failed += o.Failed
+	s.Unchecked += o.Unchecked
+}
+
+// checkWord compara el veredicto de los tres autómatas con el esperado. Una cadena con error
+// (por ejemplo, fuera de Σ) nunca pasa.
+func checkWord(res reportWord, expect config.Expectation) bool {
+	return res.Err == "" && expect.Satisfied(res.NFA) && expect.Satisfied(res.Sets) && expect.Satisfied(res.DFA) && expect.Satisfied(res.Min)
+}
+
+// describeFailure escribe el veredicto esperado frente al obtenido por cada autómata.
+func (c suiteCase) describeFailure() string {
+	if c.Error != "" {
+		return fmt.Sprintf("esperado %s; error: %s", yesNo(c.Expected), c.Error)
+	}
+	return fmt.Sprintf("esperado %s; NFA %s%s, DFA %s, minDFA %s", yesNo(c.Expected), yesNo(c.NFA), setsNote(c.NFA, c.NFASets), yesNo(c.DFA), yesNo(c.MinDFA))
+}
+
+// runSuite compila cada línea del archivo y evalúa sus cadenas con veredicto esperado.
+// Las regex se leen en la sintaxis de -syntax y se minimizan con -min. Si la regex de una línea no compila,
+// todos sus casos cuentan como fallas.
+func runSuite(path string, cf *cliFlags, sum *suiteSummary, report func(c suiteCase), invalid func(lineNo int, msg string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return scanInput(f, func(in inputLine) {
+		r, err := cf.regex(in.Regex)
+		var c *compiled
+		if err == nil {
+			c, err = compilePipeline(r, in.Sigma, cf.minAlg)
+		}
+		for _, tw := range in.Words {
+			if tw.Expect == config.NoExpectation {
+				sum.Unchecked++
+				continue
+			}
+			sc := suiteCase{File: path, Line: in.No, Regex: in.Regex, Word: tw.Word, Expected: tw.Expect.Accepts()}
+			if err != nil {
+				sc.Error = err.Error()
+			} else {
+				res, _ := c.match(tw.Word, in.Sigma != nil)
+				sc.NFA, sc.NFASets, sc.DFA, sc.MinDFA, sc.Error = res.NFA, res.Sets, res.DFA, res.Min, res.Err
+				sc.Pass = checkWord(res, tw.Expect)
+			}
+			sum.Cases++
+			if sc.Pass {
+				sum.Passed++
+			} else {
+				sum.Failed++
+			}
+			report(sc)
+		}
+	}, invalid)
+}
+
+// cmdTest ejecuta suites de prueba en el formato de input.txt con cadenas marcadas +w (aceptar) o -w
+// (rechazar). Sale con exitFalse si algún caso falla.
+func cmdTest(fs *flag.FlagSet) cliRun {
+	verbose := fs.Bool("v", false, "mostrar también los casos correctos")
+	return func(cf *cliFlags, args []string) (int, error) {
+		if cf.load != "" || cf.sigma != nil {
+			return exitUsage, errUsage("test no admite -load ni -sigma (use ;Σ o @alfabeto en la suite)")
+		}
+		if len(args) == 0 {
+			return exitUsage, errUsage("faltan los archivos de la suite")
+		}
+		var sum suiteSummary
+		cases := []suiteCase{}
+		invalidLines := 0
+		for _, path := range args {
+			err := runSuite(path, cf, &sum, func(c suiteCase) {
+				cases = append(cases, c)
+				if cf.json {
+					return
+				}
+				switch {
+				case !c.Pass:
+					fmt.Fprintf(cf.out, "FALLA %s:%d %s: %s: %s\n", c.File, c.Line, c.Regex, config.FormatWord(c.Word), c.describeFailure())
+				case *verbose:
+					fmt.Fprintf(cf.out, "ok    %s:%d %s: %s: %s\n", c.File, c.Line, c.Regex, config.FormatWord(c.Word), yesNo(c.Expected))
+				}
+			}, func(lineNo int, msg string) {
+				invalidLines++
+				fmt.Fprintf(os.Stderr, "%s:%d: %s\n", path, lineNo, msg)
+			})
+			if err != nil {
+				return exitError, err
+			}
+		}
+		cf.emit(map[string]any{"summary": sum, "cases": cases, "invalid_lines": invalidLines}, func(w io.Writer) {
+			fmt.Fprintf(w, "Resumen: %d casos, %d correctos, %d fallidos", sum.Cases, sum.Passed, sum.Failed)
+			if sum.Unchecked > 0 {
+				fmt.Fprintf(w, " (%d cadenas sin veredicto esperado)", sum.Unchecked)
+			}
+			fmt.Fprintln(w)
+		})
+		if sum.Failed > 0 || invalidLines > 0 {
+			return exitFalse, nil
+		}
+		return exitOK, nil
+	}
+}
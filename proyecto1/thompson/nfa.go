@@ -6,6 +6,7 @@ package thompson
 import (
 	"fmt"
 	"proyecto1/regex"
+	"sort"
 )
 
 const Epsilon rune = 'ε'
@@ -24,6 +25,20 @@ type NFA struct {
 	States []*State // Lista de todos los estados alcanzables
 }
 
+// NewState crea un estado con el ID indicado y sin transiciones.
+// Permite construir NFAs fuera del algoritmo de Thompson (reversión, operaciones de cerradura, etc.).
+func NewState(id int) *State {
+	return &State{
+		ID:    id,
+		Trans: make(map[rune][]*State),
+	}
+}
+
+// AddEdge agrega una transición desde s hacia 'to' usando el símbolo 'sym'.
+func (s *State) AddEdge(sym rune, to *State) {
+	s.Trans[sym] = append(s.Trans[sym], to)
+}
+
 // builder ayuda a construir el NFA, gestionando los IDs de los estados.
 type builder struct{ next int }
 
@@ -75,11 +90,12 @@ func Build(ast *regex.Node) (*NFA, error) {
 	}
 	dfs(f.start)
 
-	// Construye la lista de estados
+	// Construye la lista de estados (ordenada por ID para que sea estable entre ejecuciones)
 	states := make([]*State, 0, len(seen))
 	for _, s := range seen {
 		states = append(states, s)
 	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
 
 	return &NFA{
 		Start:  f.start,
@@ -134,6 +150,12 @@ func (b *builder) buildRec(n *regex.Node) frag {
 	}
 }
 
+// AsNFA retorna el mismo NFA. Permite usar un *NFA donde se espera cualquier autómata
+// convertible a NFA (por ejemplo, nfa.Automaton).
+func (nfa *NFA) AsNFA() *NFA {
+	return nfa
+}
+
 // AcceptingInSet retorna true si algún estado del conjunto es el estado de aceptación del NFA.
 // Se usa para verificar aceptación en la conversión NFA→DFA.
 func (nfa *NFA) AcceptingInSet(set map[*State]struct{}) bool {
@@ -0,0 +1,88 @@
+// /proyecto1/verify.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"proyecto1/config"
+	"proyecto1/nfa"
+)
+
+// defaultVerifyLen es la longitud máxima por defecto de la verificación exhaustiva (-verifylen, verify -k).
+const defaultVerifyLen = 6
+
+// describeVerdicts escribe una cadena con el veredicto de cada autómata.
+func describeVerdicts(v nfa.Verdicts) string {
+	return fmt.Sprintf("%q (NFA %s%s, DFA %s, minDFA %s)", v.Word, yesNo(v.NFA), setsNote(v.NFA, v.Sets), yesNo(v.DFA), yesNo(v.MinDFA))
+}
+
+// setsNote agrega el veredicto de la simulación del NFA por conjuntos (nfa.Simulate) cuando difiere del
+// simulador usado (nfa.NewSimulator).
+func setsNote(nfaVerdict, sets bool) string {
+	if nfaVerdict == sets {
+		return ""
+	}
+	return fmt.Sprintf(", NFA por conjuntos %s", yesNo(sets))
+}
+
+// describeCrossCheck resume el resultado de nfa.CrossCheckUpTo.
+func describeCrossCheck(first nfa.Verdicts, checked int, diverges bool, alphabet []rune, maxLen int) string {
+	if diverges {
+		return fmt.Sprintf("FALLA: los autómatas no coinciden en %s, la cadena %d de longitud ≤ %d sobre %s",
+			describeVerdicts(first), checked, maxLen, config.FormatAlphabet(alphabet))
+	}
+	return fmt.Sprintf("NFA, DFA y DFA minimizado coinciden en las %d cadenas de longitud ≤ %d sobre %s",
+		checked, maxLen, config.FormatAlphabet(alphabet))
+}
+
+// cmdVerify compara los veredictos del NFA, el DFA y el DFA minimizado en las cadenas dadas y en todas
+// las cadenas de longitud ≤ k. Sale con exitFalse si encuentra una discrepancia.
+func cmdVerify(fs *flag.FlagSet) cliRun {
+	maxLen := fs.Int("k", defaultVerifyLen, "longitud máxima de la verificación exhaustiva")
+	return func(cf *cliFlags, args []string) (int, error) {
+		if *maxLen < 0 {
+			return exitUsage, errUsage("-k debe ser mayor o igual que 0")
+		}
+		c, words, err := cf.input(args)
+		if err != nil {
+			return exitError, err
+		}
+		code := exitOK
+		results := []nfa.Verdicts{}
+		for _, tok := range words {
+			w := config.ParseWord(tok)
+			if cf.sigma != nil {
+				if err := config.ValidateWord(w, c.Alphabet); err != nil {
+					return exitError, err
+				}
+			}
+			v := nfa.CrossCheckWith(c.Sim, c.NFA, c.DFA, c.MinDFA, w)
+			if !v.Agree() {
+				code = exitFalse
+			}
+			results = append(results, v)
+		}
+		first, checked, diverges := nfa.CrossCheckUpTo(c.NFA, c.DFA, c.MinDFA, c.Alphabet, *maxLen)
+		if diverges {
+			code = exitFalse
+		}
+
+		resp := map[string]any{"words": results, "k": *maxLen, "checked": checked, "agree": code == exitOK}
+		if diverges {
+			resp["divergence"] = first
+		}
+		cf.emit(resp, func(w io.Writer) {
+			for _, v := range results {
+				mark := "ok"
+				if !v.Agree() {
+					mark = "FALLA"
+				}
+				fmt.Fprintf(w, "%-5s %s\n", mark, describeVerdicts(v))
+			}
+			fmt.Fprintf(w, "Verificación exhaustiva: %s\n", describeCrossCheck(first, checked, diverges, c.Alphabet, *maxLen))
+		})
+		return code, nil
+	}
+}